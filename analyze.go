@@ -0,0 +1,244 @@
+package qordle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Review grades a single guess against the best guess of the strategy
+type Review struct {
+	Guess string `json:"guess"`
+	Score string `json:"score"`
+	// Before is the number of candidates remaining before the guess
+	Before int `json:"before"`
+	// After is the number of candidates remaining after the guess
+	After int `json:"after"`
+	// Expected is the expected number of candidates remaining after the guess
+	Expected float64 `json:"expected"`
+	// Best is the guess chosen by the strategy from the candidates
+	Best         string  `json:"best"`
+	BestAfter    int     `json:"best_after"`
+	BestExpected float64 `json:"best_expected"`
+	// Skill is 100 if the guess was expected to leave no more candidates than the best guess
+	Skill float64 `json:"skill"`
+	// Luck is the chance, as a percentage, of the guess leaving more candidates than it did
+	Luck float64 `json:"luck"`
+}
+
+// Analysis is the review of every guess of a game
+type Analysis struct {
+	Secret   string    `json:"secret"`
+	Strategy string    `json:"strategy"`
+	Solved   bool      `json:"solved"`
+	Reviews  []*Review `json:"reviews"`
+	// Skill is the mean skill of the reviewed guesses
+	Skill float64 `json:"skill"`
+	// Luck is the mean luck of the reviewed guesses
+	Luck float64 `json:"luck"`
+}
+
+func (a *Analysis) Header() []string {
+	return []string{
+		"guess", "score", "before", "after", "expected",
+		"best", "best_after", "best_expected", "skill", "luck",
+	}
+}
+
+func (a *Analysis) Rows() [][]string {
+	rows := make([][]string, 0, len(a.Reviews)+1)
+	for _, r := range a.Reviews {
+		rows = append(rows, []string{
+			r.Guess,
+			r.Score,
+			strconv.Itoa(r.Before),
+			strconv.Itoa(r.After),
+			strconv.FormatFloat(r.Expected, 'f', 2, 64),
+			r.Best,
+			strconv.Itoa(r.BestAfter),
+			strconv.FormatFloat(r.BestExpected, 'f', 2, 64),
+			strconv.FormatFloat(r.Skill, 'f', 1, 64),
+			strconv.FormatFloat(r.Luck, 'f', 1, 64),
+		})
+	}
+	return append(rows, []string{
+		"total", "", "", "", "", "", "", "",
+		strconv.FormatFloat(a.Skill, 'f', 1, 64),
+		strconv.FormatFloat(a.Luck, 'f', 1, 64),
+	})
+}
+
+// buckets returns the size of the bucket of candidates sharing each candidate's feedback for the guess
+func buckets(candidates Dictionary, guess string) []int {
+	counts := make(map[int]int)
+	patterns := make([]int, len(candidates))
+	for i := range candidates {
+		patterns[i] = feedback(candidates[i], guess)
+		counts[patterns[i]]++
+	}
+	sizes := make([]int, len(candidates))
+	for i := range patterns {
+		sizes[i] = counts[patterns[i]]
+	}
+	return sizes
+}
+
+// expected number of candidates remaining after the guess
+func expected(sizes []int) float64 {
+	if len(sizes) == 0 {
+		return 0
+	}
+	var total int
+	for _, size := range sizes {
+		total += size
+	}
+	return float64(total) / float64(len(sizes))
+}
+
+// luck is the chance of the guess leaving more candidates than it did, counting ties as half
+func luck(sizes []int, after int) float64 {
+	if len(sizes) == 0 {
+		return 0
+	}
+	var more float64
+	for _, size := range sizes {
+		switch {
+		case size > after:
+			more++
+		case size == after:
+			more += 0.5
+		}
+	}
+	return 100 * more / float64(len(sizes))
+}
+
+// Analyze reviews each guess made for the secret against the best guess of the strategy
+func Analyze(
+	ctx context.Context, strategy Strategy, dictionary Dictionary, secret string, guesses ...string,
+) (*Analysis, error) {
+	if len(guesses) == 0 {
+		return nil, errors.New("missing guesses")
+	}
+	if len(secret) > maxOpenerLength {
+		return nil, fmt.Errorf("length must not exceed %d", maxOpenerLength)
+	}
+	// the candidates are lowercase so compare against lowercase words as Score does
+	secret = strings.ToLower(secret)
+	guesses = slices.Clone(guesses)
+	for i := range guesses {
+		guesses[i] = strings.ToLower(guesses[i])
+	}
+	scores, err := Score(secret, guesses...)
+	if err != nil {
+		return nil, err
+	}
+	analysis := &Analysis{
+		Secret:   secret,
+		Strategy: strategy.String(),
+		Solved:   guesses[len(guesses)-1] == secret,
+	}
+	candidates := Filter(dictionary, Length(len(secret)), IsLower())
+	var reviewed int
+	for i, guess := range guesses {
+		review := &Review{Guess: guess, Score: scores[i], Before: len(candidates)}
+		filter, err := Guess(scores[i])
+		if err != nil {
+			return nil, err
+		}
+		after := Filter(candidates, filter)
+		review.After = len(after)
+		if len(candidates) > 0 {
+			knowledge, err := NewKnowledge(scores[:i]...)
+			if err != nil {
+				return nil, err
+			}
+			ranked, err := ApplyContext(ctx, strategy, candidates, knowledge)
+			if err != nil {
+				return nil, err
+			}
+			if len(ranked) > 0 {
+				review.Best = ranked[0]
+				best, err := Score(secret, review.Best)
+				if err != nil {
+					return nil, err
+				}
+				filter, err = Guess(best...)
+				if err != nil {
+					return nil, err
+				}
+				review.BestAfter = len(Filter(candidates, filter))
+				review.BestExpected = expected(buckets(candidates, review.Best))
+			}
+			sizes := buckets(candidates, guess)
+			review.Expected = expected(sizes)
+			review.Luck = luck(sizes, review.After)
+			review.Skill = 100
+			if review.BestExpected > 0 && review.Expected > review.BestExpected {
+				review.Skill = 100 * review.BestExpected / review.Expected
+			}
+			analysis.Skill += review.Skill
+			analysis.Luck += review.Luck
+			reviewed++
+		}
+		analysis.Reviews = append(analysis.Reviews, review)
+		candidates = after
+	}
+	if reviewed > 0 {
+		analysis.Skill /= float64(reviewed)
+		analysis.Luck /= float64(reviewed)
+	}
+	return analysis, nil
+}
+
+func CommandAnalyze() *cli.Command {
+	return &cli.Command{
+		Name:      "analyze",
+		Category:  categoryWordle,
+		Usage:     "Review the guesses of a game against the best guesses of the strategy",
+		ArgsUsage: "[<secret>] <guess> [, <guess>]",
+		Description: "For each guess report the candidates remaining before and after, the expected " +
+			"number remaining and how it compares with the best guess of the strategy. Skill " +
+			"grades the expected outcome of each guess against the best guess and luck is the " +
+			"chance of the guess having left more candidates than it did. With a date the secret " +
+			"is the solution of the daily puzzle and every argument is a guess.",
+		Flags: slices.Concat(
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:  "date",
+					Usage: "analyze the daily puzzle for `today` or YYYY-MM-DD",
+				},
+			},
+			wordlistFlags(), strategyFlags(), ngramFlags(), dailyFlags(),
+		),
+		Action: func(c *cli.Context) error {
+			secret, guesses := c.Args().First(), c.Args().Tail()
+			switch {
+			case c.IsSet("date"):
+				if c.NArg() == 0 {
+					return errors.New("expected at least one guess")
+				}
+				d, err := daily(c)
+				if err != nil {
+					return err
+				}
+				secret, guesses = d.Solution, c.Args().Slice()
+			case c.NArg() < 2:
+				return errors.New("expected a secret and at least one guess")
+			}
+			dictionary, strategy, err := prepare(c, "possible", "solutions")
+			if err != nil {
+				return err
+			}
+			analysis, err := Analyze(c.Context, strategy, dictionary, secret, guesses...)
+			if err != nil {
+				return err
+			}
+			return Runtime(c).Encoder.Encode(analysis)
+		},
+	}
+}
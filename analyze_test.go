@@ -0,0 +1,139 @@
+package qordle_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+
+	analysis, err := qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "table", "soare", "clint", "table")
+	a.NoError(err)
+	a.True(analysis.Solved)
+	a.Len(analysis.Reviews, 3)
+	for i, review := range analysis.Reviews {
+		if i > 0 {
+			a.Equal(analysis.Reviews[i-1].After, review.Before)
+		}
+		a.LessOrEqual(review.After, review.Before)
+		a.GreaterOrEqual(review.Expected, 1.0)
+		a.NotEmpty(review.Best)
+		a.InDelta(50, review.Luck, 50)
+		a.InDelta(50, review.Skill, 50)
+	}
+	a.Equal(1, analysis.Reviews[2].After)
+
+	// the strategy's choice is graded as the best possible
+	for _, review := range analysis.Reviews {
+		if review.Guess == review.Best {
+			a.Equal(100.0, review.Skill)
+		}
+	}
+
+	// mixed case is analyzed as lowercase
+	guesses := []string{"SOARE", "Clint", "TABLE"}
+	mixed, err := qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "Table", guesses...)
+	a.NoError(err)
+	a.Equal(analysis, mixed)
+	a.Equal([]string{"SOARE", "Clint", "TABLE"}, guesses)
+	lower, err := qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "shine", "tares")
+	a.NoError(err)
+	mixed, err = qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "shine", "TARES")
+	a.NoError(err)
+	a.Equal(lower, mixed)
+	a.Equal("tares", mixed.Reviews[0].Guess)
+
+	_, err = qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "table")
+	a.EqualError(err, "missing guesses")
+	_, err = qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "table", "tables")
+	a.ErrorIs(err, qordle.ErrInvalidLength)
+	_, err = qordle.Analyze(context.Background(), new(qordle.Frequency), dt, "abcdefghijk", "abcdefghijk")
+	a.EqualError(err, "length must not exceed 10")
+}
+
+func TestAnalyzeCommand(t *testing.T) {
+	a := assert.New(t)
+	decode := func(c *cli.Context) *qordle.Analysis {
+		var res qordle.Analysis
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return &res
+	}
+	for _, tt := range []harness{
+		{
+			name: "analyze",
+			args: []string{"analyze", "table", "soare", "clint", "table"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("frequency", res.Strategy)
+				a.True(res.Solved)
+				a.Equal([]int{12947, 156, 5}, []int{res.Reviews[0].Before, res.Reviews[1].Before, res.Reviews[2].Before})
+				a.Equal("aeros", res.Reviews[0].Best)
+				a.Equal("c.lin.t", res.Reviews[1].Score)
+				a.InDelta(63.6, res.Reviews[2].Skill, 0.1)
+				a.InDelta(87.9, res.Skill, 0.1)
+				a.InDelta(69.7, res.Luck, 0.1)
+				return nil
+			},
+		},
+		{
+			name: "unsolved",
+			args: []string{"analyze", "-s", "position", "-w", "solutions", "table", "soare"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("position", res.Strategy)
+				a.False(res.Solved)
+				a.Len(res.Reviews, 1)
+				return nil
+			},
+		},
+		{
+			name: "text",
+			args: []string{"analyze", "table", "soare", "clint", "table"},
+			before: func(c *cli.Context) error {
+				enc, err := qordle.NewEncoder(c.App.Writer, "text")
+				qordle.Runtime(c).Encoder = enc
+				return err
+			},
+			after: func(c *cli.Context) error {
+				lines := strings.Split(strings.TrimSpace(c.App.Writer.(*bytes.Buffer).String()), "\n")
+				a.Len(lines, 4)
+				a.True(strings.HasPrefix(lines[0], "soare\tso.arE\t12947\t156\t"))
+				a.True(strings.HasPrefix(lines[3], "total\t"))
+				return nil
+			},
+		},
+		{
+			name: "missing guesses",
+			args: []string{"analyze", "table"},
+			err:  "expected a secret and at least one guess",
+		},
+		{
+			name: "invalid strategy",
+			args: []string{"analyze", "-s", "foobar", "table", "soare"},
+			err:  "unknown strategy `foobar`",
+		},
+		{
+			name: "invalid length",
+			args: []string{"analyze", "table", "tables"},
+			err:  qordle.ErrInvalidLength.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandAnalyze)
+		})
+	}
+}
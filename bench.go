@@ -0,0 +1,378 @@
+package qordle
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	sys "runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// winning is the maximum number of rounds for a game to count as a win
+const winning = 6
+
+// Benchmark summarizes the games played by a strategy configuration
+type Benchmark struct {
+	Config    string      `json:"config"`
+	Strategy  string      `json:"strategy"`
+	Games     int         `json:"games"`
+	Wins      int         `json:"wins"`
+	Pct       float64     `json:"pct"`
+	Failures  int         `json:"failures"`
+	Mean      float64     `json:"mean"`
+	Median    float64     `json:"median"`
+	Max       int         `json:"max"`
+	Histogram map[int]int `json:"histogram"`
+	Elapsed   int64       `json:"elapsed"`
+}
+
+// Outcome is the result of a single game
+type Outcome struct {
+	Secret  string `json:"secret"`
+	Rounds  int    `json:"rounds"`
+	Success bool   `json:"success"`
+	Elapsed int64  `json:"elapsed"`
+}
+
+func outcome(board *Scoreboard) Outcome {
+	n := len(board.Rounds)
+	return Outcome{
+		Secret:  board.Target,
+		Rounds:  board.guesses(),
+		Success: n > 0 && board.Rounds[n-1].Success,
+		Elapsed: board.Elapsed,
+	}
+}
+
+// win returns true if the game was won within the winning number of rounds
+func (o Outcome) win() bool {
+	return o.Success && o.Rounds <= winning
+}
+
+// benchmark accumulates the outcomes into a summary
+func benchmark(config, strategy string, outcomes []Outcome) *Benchmark {
+	b := &Benchmark{Config: config, Strategy: strategy, Games: len(outcomes), Histogram: make(map[int]int)}
+	if len(outcomes) == 0 {
+		return b
+	}
+	var total int
+	rounds := make([]int, len(outcomes))
+	for i, o := range outcomes {
+		b.Elapsed += o.Elapsed
+		switch {
+		case !o.Success:
+			b.Failures++
+		case o.win():
+			b.Wins++
+		}
+		rounds[i] = o.Rounds
+		total += o.Rounds
+		b.Histogram[o.Rounds]++
+		b.Max = max(b.Max, o.Rounds)
+	}
+	sort.Ints(rounds)
+	switch mid := len(rounds) / 2; len(rounds) % 2 {
+	case 0:
+		b.Median = float64(rounds[mid-1]+rounds[mid]) / 2
+	default:
+		b.Median = float64(rounds[mid])
+	}
+	b.Mean = float64(total) / float64(len(outcomes))
+	b.Pct = 100 * float64(b.Wins) / float64(len(outcomes))
+	return b
+}
+
+// histogram formats the histogram ordered by rounds
+func (b *Benchmark) histogram() string {
+	rounds := make([]int, 0, len(b.Histogram))
+	for n := range b.Histogram {
+		rounds = append(rounds, n)
+	}
+	sort.Ints(rounds)
+	bins := make([]string, len(rounds))
+	for i, n := range rounds {
+		bins[i] = fmt.Sprintf("%d:%d", n, b.Histogram[n])
+	}
+	return strings.Join(bins, " ")
+}
+
+func (b *Benchmark) Header() []string {
+	return []string{
+		"config", "strategy", "games", "wins", "pct", "failures",
+		"mean", "median", "max", "histogram", "elapsed",
+	}
+}
+
+func (b *Benchmark) Rows() [][]string {
+	return [][]string{{
+		b.Config,
+		b.Strategy,
+		strconv.Itoa(b.Games),
+		strconv.Itoa(b.Wins),
+		strconv.FormatFloat(b.Pct, 'f', 2, 64),
+		strconv.Itoa(b.Failures),
+		strconv.FormatFloat(b.Mean, 'f', 2, 64),
+		strconv.FormatFloat(b.Median, 'f', 1, 64),
+		strconv.Itoa(b.Max),
+		b.histogram(),
+		strconv.FormatInt(b.Elapsed, 10),
+	}}
+}
+
+// contender is a strategy configuration to benchmark
+type contender struct {
+	config   string
+	strategy string
+	game     *Game
+}
+
+// contend parses a configuration using the same flags as `play`, eg "-S -s freq -s pos --start tares"
+func contend(c *cli.Context, dictionary Dictionary, config string) (*contender, error) {
+	var names cli.StringSlice
+	fs := flag.NewFlagSet(config, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&names, "s", "")
+	fs.Var(&names, "strategy", "")
+	speculate := fs.Bool("S", false, "")
+	fs.BoolVar(speculate, "speculate", false, "")
+	start := fs.String("t", "", "")
+	fs.StringVar(start, "start", "", "")
+	if err := fs.Parse(strings.Fields(config)); err != nil {
+		return nil, fmt.Errorf("invalid config `%s`: %w", config, err)
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("invalid config `%s`: unexpected arguments %v", config, fs.Args())
+	}
+	if len(names.Value()) == 0 {
+		return nil, fmt.Errorf("invalid config `%s`: missing strategy", config)
+	}
+	strategy, err := build(Runtime(c).Strategies, names.Value())
+	if err != nil {
+		return nil, err
+	}
+	SetWorkers(strategy, c.Int("workers"))
+	SetTables(strategy, lettered(c, dictionary))
+	model, err := modeled(c, dictionary)
+	if err != nil {
+		return nil, err
+	}
+	SetModel(strategy, model)
+	if *speculate {
+		strategy = NewSpeculator(dictionary, strategy)
+	}
+	return &contender{
+		config:   config,
+		strategy: strategy.String(),
+		game: NewGame(
+			WithStrategy(strategy),
+			WithDictionary(dictionary),
+			WithStart(*start),
+			WithRounds(c.Int("rounds"))),
+	}, nil
+}
+
+// Sample returns `n` words from the dictionary chosen reproducibly by the seed
+func Sample(dictionary Dictionary, n int, seed uint64) Dictionary {
+	words := slices.Clone(dictionary)
+	// sort first as the order of the dictionary is not guaranteed
+	sort.Strings(words)
+	//nolint:gosec // G404: reproducibility matters, not security
+	rng := rand.New(rand.NewPCG(seed, seed))
+	rng.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+	if n > 0 && n < len(words) {
+		words = words[:n]
+	}
+	return words
+}
+
+// tournament plays every secret for every contender using a pool of workers
+func tournament(
+	ctx context.Context, contenders []*contender, secrets []string, concurrent int, bar *pb.ProgressBar,
+) ([][]Outcome, error) {
+	type job struct {
+		contender, secret int
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		for i := range contenders {
+			for j := range secrets {
+				select {
+				case <-ctx.Done():
+					return
+				case jobs <- job{contender: i, secret: j}:
+				}
+			}
+		}
+	}()
+
+	outcomes := make([][]Outcome, len(contenders))
+	for i := range outcomes {
+		outcomes[i] = make([]Outcome, len(secrets))
+	}
+	var wg sync.WaitGroup
+	for range workers(concurrent, len(contenders)*len(secrets)) {
+		wg.Go(func() {
+			for j := range jobs {
+				board, err := contenders[j.contender].game.Play(ctx, secrets[j.secret])
+				if err != nil {
+					cancel(fmt.Errorf("%s: %w", contenders[j.contender].config, err))
+					return
+				}
+				outcomes[j.contender][j.secret] = outcome(board)
+				bar.Increment()
+			}
+		})
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// contest plays the secrets for each of the configurations
+func contest(c *cli.Context, configs, secrets []string) ([]*contender, [][]Outcome, error) {
+	dictionary, err := wordlists(c, "possible", "solutions")
+	if err != nil {
+		return nil, nil, err
+	}
+	contenders := make([]*contender, len(configs))
+	for i := range configs {
+		contenders[i], err = contend(c, dictionary, configs[i])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	writer := io.Discard
+	if c.Bool("progress") {
+		writer = c.App.ErrWriter
+	}
+	bar := pb.New(len(contenders) * len(secrets)).SetWriter(writer).Start()
+	defer bar.Finish()
+	outcomes, err := tournament(c.Context, contenders, secrets, c.Int("concurrent"), bar)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contenders, outcomes, nil
+}
+
+// sample the secrets to play
+func sample(c *cli.Context) ([]string, error) {
+	secrets, err := resolve(c, c.String("secrets"))
+	if err != nil {
+		return nil, err
+	}
+	return Sample(secrets, c.Int("sample"), c.Uint64("seed")), nil
+}
+
+func bench(c *cli.Context) error {
+	defer func(t time.Time) {
+		log.Info().Dur("elapsed", time.Since(t)).Msg(c.Command.Name)
+	}(time.Now())
+
+	configs := c.StringSlice("config")
+	if len(configs) == 0 {
+		return errors.New("missing strategy configurations")
+	}
+	secrets, err := sample(c)
+	if err != nil {
+		return err
+	}
+	contenders, outcomes, err := contest(c, configs, secrets)
+	if err != nil {
+		return err
+	}
+
+	benchmarks := make([]*Benchmark, len(contenders))
+	for i := range contenders {
+		benchmarks[i] = benchmark(contenders[i].config, contenders[i].strategy, outcomes[i])
+	}
+	sort.SliceStable(benchmarks, func(i, j int) bool {
+		return benchmarks[i].Wins > benchmarks[j].Wins
+	})
+
+	return Runtime(c).Encoder.Encode(benchmarks)
+}
+
+func benchFlags() []cli.Flag {
+	return append(
+		[]cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "strategy configuration to play",
+			},
+			&cli.StringFlag{
+				Name:  "secrets",
+				Usage: "the word list, embedded or a file, from which to sample secrets",
+				Value: "possible",
+			},
+			&cli.IntFlag{
+				Name:    "sample",
+				Aliases: []string{"n"},
+				Usage:   "number of secrets to play, all secrets if less than one",
+				Value:   100,
+			},
+			&cli.Uint64Flag{
+				Name:  "seed",
+				Usage: "seed for sampling the secrets",
+				Value: 1,
+			},
+			&cli.IntFlag{
+				Name:  "concurrent",
+				Usage: "number of games to play concurrently",
+				Value: sys.NumCPU(),
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "number of workers used by each strategy supporting concurrency",
+				Value: 1,
+			},
+			&cli.IntFlag{
+				Name:    "rounds",
+				Aliases: []string{"r"},
+				Usage:   "max rounds not to exceed `rounds` * len(secret)",
+				Value:   rounds,
+			},
+			&cli.BoolFlag{
+				Name:    "progress",
+				Aliases: []string{"B"},
+				Usage:   "display a progress bar",
+				Value:   false,
+			},
+		},
+		append(wordlistFlags(), ngramFlags()...)...,
+	)
+}
+
+func CommandBench() *cli.Command {
+	return &cli.Command{
+		Name:     "bench",
+		Category: categoryWordle,
+		Usage:    "Benchmark strategy configurations by playing a sample of secrets",
+		Description: "Each configuration uses the strategy flags of `play`, " +
+			"eg --config \"-S -s frequency -s position --start tares\"",
+		Flags:  benchFlags(),
+		Action: bench,
+	}
+}
@@ -0,0 +1,159 @@
+package qordle_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	words := qordle.Dictionary{"brain", "raise", "sport", "maths", "table", "found"}
+	reversed := qordle.Dictionary{"found", "table", "maths", "sport", "raise", "brain"}
+
+	x := qordle.Sample(words, 3, 7)
+	a.Len(x, 3)
+	a.Equal(x, qordle.Sample(words, 3, 7))
+	a.Equal(x, qordle.Sample(reversed, 3, 7), "the order of the dictionary does not matter")
+	a.Len(qordle.Sample(words, 0, 7), len(words))
+	a.Len(qordle.Sample(words, 100, 7), len(words))
+	a.ElementsMatch(words, qordle.Sample(words, -1, 7))
+	a.Equal(qordle.Dictionary{"brain", "raise"}, words[:2], "the dictionary is not modified")
+}
+
+func TestBenchCommand(t *testing.T) {
+	a := assert.New(t)
+	secrets := filepath.Join(t.TempDir(), "secrets.txt")
+	a.NoError(os.WriteFile(secrets, []byte("TABLE\nshine\n"), 0o600))
+	for _, tt := range []harness{
+		{
+			name: "json",
+			args: []string{
+				"bench", "-n", "5", "--seed", "3",
+				"-c", "-s pos --start soare", "-c", "-S -s freq",
+			},
+			after: func(c *cli.Context) error {
+				var res []*qordle.Benchmark
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Len(res, 2)
+				strategies := make([]string, len(res))
+				for i := range res {
+					a.Equal(5, res[i].Games)
+					var n int
+					for _, v := range res[i].Histogram {
+						n += v
+					}
+					a.Equal(5, n)
+					a.LessOrEqual(res[i].Mean, float64(res[i].Max))
+					a.LessOrEqual(res[i].Median, float64(res[i].Max))
+					a.InDelta(100*float64(res[i].Wins)/5, res[i].Pct, 0.001)
+					strategies[i] = res[i].Strategy
+				}
+				a.ElementsMatch([]string{"position", "speculate{frequency}"}, strategies)
+				a.GreaterOrEqual(res[0].Wins, res[1].Wins)
+				return nil
+			},
+		},
+		{
+			name: "csv",
+			args: []string{"bench", "-n", "3", "-c", "-s pos --start soare"},
+			before: func(c *cli.Context) error {
+				enc, err := qordle.NewEncoder(c.App.Writer, "csv")
+				qordle.Runtime(c).Encoder = enc
+				return err
+			},
+			after: func(c *cli.Context) error {
+				records, err := csv.NewReader(c.App.Writer.(io.Reader)).ReadAll()
+				a.NoError(err)
+				a.Len(records, 2)
+				a.Equal("config", records[0][0])
+				a.Equal([]string{"-s pos --start soare", "position", "3"}, records[1][:3])
+				return nil
+			},
+		},
+		{
+			name: "table",
+			args: []string{"bench", "-B", "-n", "3", "--concurrent", "2", "-c", "-s pos"},
+			before: func(c *cli.Context) error {
+				enc, err := qordle.NewEncoder(c.App.Writer, "table")
+				qordle.Runtime(c).Encoder = enc
+				return err
+			},
+			after: func(c *cli.Context) error {
+				lines := strings.Split(strings.TrimSpace(c.App.Writer.(*bytes.Buffer).String()), "\n")
+				a.Len(lines, 2)
+				a.True(strings.HasPrefix(lines[0], "config"))
+				a.True(strings.HasPrefix(lines[1], "-s pos"))
+				return nil
+			},
+		},
+		{
+			name: "missing configurations",
+			args: []string{"bench"},
+			err:  "missing strategy configurations",
+		},
+		{
+			name: "missing strategy",
+			args: []string{"bench", "-c", "--start soare"},
+			err:  "invalid config `--start soare`: missing strategy",
+		},
+		{
+			name: "unknown flag",
+			args: []string{"bench", "-c", "-s pos -x"},
+			err:  "invalid config `-s pos -x`",
+		},
+		{
+			name: "unexpected arguments",
+			args: []string{"bench", "-c", "-s pos table"},
+			err:  "invalid config `-s pos table`: unexpected arguments [table]",
+		},
+		{
+			name: "unknown strategy",
+			args: []string{"bench", "-c", "-s foobar"},
+			err:  "unknown strategy `foobar`",
+		},
+		{
+			name: "secrets from a file",
+			args: []string{"bench", "--secrets", secrets, "-c", "-s pos --start soare"},
+			after: func(c *cli.Context) error {
+				var res []qordle.Benchmark
+				a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+				a.Len(res, 1)
+				a.Equal(2, res[0].Games)
+				return nil
+			},
+		},
+		{
+			name: "invalid secrets",
+			args: []string{"bench", "--secrets", "foobar", "-c", "-s pos"},
+			err:  "invalid wordlist `foobar`",
+		},
+		{
+			name: "invalid wordlist",
+			args: []string{"bench", "-w", "foobar", "-c", "-s pos"},
+			err:  "invalid wordlist `foobar`",
+		},
+		{
+			name: "game error",
+			args: []string{"bench", "-n", "3", "-c", "-s pos --start toolong"},
+			err:  "-s pos --start toolong: secret and guess lengths do not match",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandBench)
+		})
+	}
+}
@@ -4,13 +4,14 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/bzimmer/manual"
+	"github.com/mattn/go-isatty"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
@@ -18,20 +19,27 @@ import (
 	"github.com/bzimmer/qordle"
 )
 
-type strategies struct {
-	strategies qordle.Trie[qordle.Strategy]
-}
-
-func (s strategies) Strategy(prefix string) (qordle.Strategy, error) {
-	strategy := s.strategies.Value(prefix)
-	if strategy != nil {
-		return strategy, nil
+func plugins(c *cli.Context) ([]*qordle.Plugin, error) {
+	var res []*qordle.Plugin
+	if filename := c.Path("plugins"); filename != "" {
+		fp, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer fp.Close()
+		res, err = qordle.ReadPlugins(fp)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return nil, fmt.Errorf("unknown strategy `%s`", prefix)
-}
-
-func (s strategies) Strategies() []string {
-	return s.strategies.Strings()
+	for _, spec := range c.StringSlice("plugin") {
+		plugin, err := qordle.ParsePlugin(spec)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, plugin)
+	}
+	return res, nil
 }
 
 func main() {
@@ -48,9 +56,22 @@ func main() {
 			},
 			&cli.BoolFlag{
 				Name:  "monochrome",
-				Usage: "disable color output",
+				Usage: "disable color output of logs and tiles",
 				Value: false,
 			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output format, one of " + strings.Join(qordle.Formats, ", "),
+				Value: "json",
+			},
+			&cli.StringSliceFlag{
+				Name:  "plugin",
+				Usage: "register an external strategy as `name=command [arg ...]`",
+			},
+			&cli.PathFlag{
+				Name:  "plugins",
+				Usage: "register the external strategies from a JSON configuration file",
+			},
 		},
 		Before: func(c *cli.Context) error {
 			level := zerolog.InfoLevel
@@ -68,34 +89,61 @@ func main() {
 				},
 			)
 
-			trie := qordle.Trie[qordle.Strategy]{}
-			for _, strategy := range []qordle.Strategy{
-				new(qordle.Alpha),
-				new(qordle.Bigram),
-				new(qordle.Elimination),
-				new(qordle.Frequency),
-				new(qordle.Position),
-			} {
-				trie.Add(strategy.String(), strategy)
+			registry := qordle.DefaultRegistry()
+			plugins, err := plugins(c)
+			if err != nil {
+				return err
+			}
+			for _, plugin := range plugins {
+				if err = registry.Register(plugin.String(), plugin.Description(), func() qordle.Strategy {
+					return plugin
+				}); err != nil {
+					return err
+				}
+			}
+
+			var opts []qordle.EncoderOption
+			if fp, ok := c.App.Writer.(*os.File); ok && isatty.IsTerminal(fp.Fd()) {
+				color := !c.Bool("monochrome") && os.Getenv("NO_COLOR") == ""
+				opts = append(opts, qordle.WithTiles(qordle.NewTiles(color)))
+			}
+			encoder, err := qordle.NewEncoder(c.App.Writer, c.String("format"), opts...)
+			if err != nil {
+				return err
 			}
 
 			c.App.Metadata = map[string]any{
 				qordle.RuntimeKey: &qordle.Rt{
 					Grab:       &http.Client{Timeout: 2 * time.Second},
-					Encoder:    json.NewEncoder(c.App.Writer),
+					Encoder:    encoder,
 					Start:      time.Now(),
-					Strategies: &strategies{strategies: trie},
+					Strategies: registry,
 				},
 			}
 
 			return nil
 		},
+		After: func(c *cli.Context) error {
+			// the encoder is missing if the app failed before creating the runtime
+			if rt, ok := c.App.Metadata[qordle.RuntimeKey].(*qordle.Rt); ok {
+				return qordle.Flush(rt.Encoder)
+			}
+			return nil
+		},
 		Commands: []*cli.Command{
+			qordle.CommandAnalyze(),
+			qordle.CommandBench(),
+			qordle.CommandCompare(),
+			qordle.CommandDaily(),
 			qordle.CommandDigits(),
+			qordle.CommandHistory(),
+			qordle.CommandInfer(),
 			qordle.CommandLetterBoxed(),
+			qordle.CommandOpeners(),
 			qordle.CommandOrder(),
 			qordle.CommandPlay(),
 			qordle.CommandRanks(),
+			qordle.CommandReverse(),
 			qordle.CommandScore(),
 			qordle.CommandStrategies(),
 			qordle.CommandSuggest(),
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,39 +18,6 @@ import (
 	"github.com/bzimmer/qordle"
 )
 
-// strategyDescriptions returns a map of each strategy name to a human-readable description.
-func strategyDescriptions() map[string]string {
-	return map[string]string{
-		"alpha":       "Sort the word list alphabetically",
-		"bigram":      "Rank words by bigram frequency of their letters",
-		"elimination": "Rank words by how many candidates each guess eliminates",
-		"frequency":   "Rank words by the frequency of their letters in the remaining list",
-		"position":    "Rank words by how often each letter appears in its position",
-	}
-}
-
-// registry returns a Trie holding all available strategies keyed by name.
-func registry() *qordle.Trie[qordle.Strategy] {
-	t := &qordle.Trie[qordle.Strategy]{}
-	for _, s := range []qordle.Strategy{
-		new(qordle.Alpha),
-		new(qordle.Bigram),
-		new(qordle.Elimination),
-		new(qordle.Frequency),
-		new(qordle.Position),
-	} {
-		t.Add(s.String(), s)
-	}
-	return t
-}
-
-// strategyNames returns the sorted list of available strategy names.
-func strategyNames() []string {
-	names := registry().Strings()
-	sort.Strings(names)
-	return names
-}
-
 // buildStrategy constructs a strategy from the given names, chaining them
 // when more than one is provided. Falls back to frequency+position when
 // no names are supplied.
@@ -58,12 +25,12 @@ func buildStrategy(names []string) (qordle.Strategy, error) {
 	if len(names) == 0 {
 		names = []string{"frequency", "position"}
 	}
-	reg := registry()
+	registry := qordle.DefaultRegistry()
 	strategies := make([]qordle.Strategy, 0, len(names))
 	for _, name := range names {
-		s := reg.Value(name)
-		if s == nil {
-			return nil, fmt.Errorf("unknown strategy %q", name)
+		s, err := registry.Strategy(name)
+		if err != nil {
+			return nil, err
 		}
 		strategies = append(strategies, s)
 	}
@@ -74,13 +41,7 @@ func buildStrategy(names []string) (qordle.Strategy, error) {
 }
 
 func strategies(c echo.Context) error {
-	names := strategyNames()
-	descs := strategyDescriptions()
-	result := make(map[string]string, len(names))
-	for _, name := range names {
-		result[name] = descs[name]
-	}
-	return c.JSONPretty(http.StatusOK, result, " ")
+	return c.JSONPretty(http.StatusOK, qordle.DefaultRegistry().Descriptions(), " ")
 }
 
 func play(c echo.Context) error {
@@ -98,13 +59,37 @@ func play(c echo.Context) error {
 		qordle.WithDictionary(dictionary),
 		qordle.WithStart(c.QueryParam("start")),
 		qordle.WithStrategy(strategy))
-	scoreboard, err := game.Play(secret)
+	scoreboard, err := game.Play(c.Request().Context(), secret)
 	if err != nil {
 		return err
 	}
 	return c.JSONPretty(http.StatusOK, scoreboard, " ")
 }
 
+// integer returns the query parameter as an integer, defaulting to zero
+func integer(c echo.Context, name string) (int, error) {
+	param := c.QueryParam(name)
+	if param == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s `%s`", name, param)
+	}
+	return value, nil
+}
+
+// page returns the offset and limit query parameters, defaulting to zero
+func page(c echo.Context) (offset, limit int, err error) {
+	if offset, err = integer(c, "offset"); err != nil {
+		return 0, 0, err
+	}
+	if limit, err = integer(c, "limit"); err != nil {
+		return 0, 0, err
+	}
+	return offset, limit, nil
+}
+
 func suggest(c echo.Context) error {
 	dictionary, err := qordle.Read("solutions")
 	if err != nil {
@@ -117,12 +102,39 @@ func suggest(c echo.Context) error {
 	if c.QueryParam("speculate") == "true" {
 		strategy = qordle.NewSpeculator(dictionary, strategy)
 	}
-	guesser, err := qordle.Guess(strings.Split(c.Param("guesses"), " ")...)
+	offset, limit, err := page(c)
 	if err != nil {
-		return err
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	lies, err := integer(c, "lies")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	query, err := qordle.NewQuery(c.QueryParams())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	fns, err := query.Filters()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	dictionary = qordle.Filter(dictionary, fns...)
+	guesses := strings.Split(c.Param("guesses"), " ")
+	suggestion, err := qordle.Suggest(c.Request().Context(), strategy, dictionary, lies, guesses...)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err = suggestion.Page(offset, limit); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if c.QueryParam("detailed") == "true" {
+		return c.JSONPretty(http.StatusOK, suggestion, " ")
+	}
+	words := make(qordle.Dictionary, len(suggestion.Words))
+	for i := range suggestion.Words {
+		words[i] = suggestion.Words[i].Word
 	}
-	dictionary = strategy.Apply(qordle.Filter(dictionary, guesser))
-	return c.JSONPretty(http.StatusOK, dictionary, " ")
+	return c.JSONPretty(http.StatusOK, words, " ")
 }
 
 func newEngine() *echo.Echo {
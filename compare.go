@@ -0,0 +1,290 @@
+package qordle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Baseline is the recorded outcome of a strategy configuration for later comparison
+type Baseline struct {
+	Config   string    `json:"config"`
+	Strategy string    `json:"strategy"`
+	Secrets  string    `json:"secrets"`
+	Sample   int       `json:"sample"`
+	Seed     uint64    `json:"seed"`
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Comparison is the paired comparison of a candidate with a baseline on the same secrets
+type Comparison struct {
+	Baseline  *Benchmark `json:"baseline"`
+	Candidate *Benchmark `json:"candidate"`
+	// WinDelta is the difference in win percentage, positive if the candidate is better
+	WinDelta float64 `json:"win_delta"`
+	// MeanDelta is the difference in mean guesses, negative if the candidate is better
+	MeanDelta float64 `json:"mean_delta"`
+	// Wins are the secrets the candidate solved in fewer rounds
+	Wins []string `json:"wins"`
+	// Losses are the secrets the candidate solved in more rounds
+	Losses []string `json:"losses"`
+	Ties   int      `json:"ties"`
+	// PValue is the two-sided sign test of the wins and losses
+	PValue    float64 `json:"p_value"`
+	Regressed bool    `json:"regressed"`
+}
+
+// rank of the outcome by rounds, failures ranking after every success
+func (o Outcome) rank() int {
+	if o.Success {
+		return o.Rounds
+	}
+	return math.MaxInt
+}
+
+// SignTest returns the two-sided p-value of the exact binomial sign test
+func SignTest(wins, losses int) float64 {
+	n := wins + losses
+	if n == 0 {
+		return 1
+	}
+	// probability of observing k or fewer successes from n fair coin flips
+	k := min(wins, losses)
+	lgn, _ := math.Lgamma(float64(n + 1))
+	var p float64
+	for i := 0; i <= k; i++ {
+		lgi, _ := math.Lgamma(float64(i + 1))
+		lgni, _ := math.Lgamma(float64(n - i + 1))
+		p += math.Exp(lgn - lgi - lgni - float64(n)*math.Ln2)
+	}
+	return math.Min(1, 2*p)
+}
+
+// Compare the outcomes of the candidate and baseline paired by secret
+func Compare(baseline, candidate *Benchmark, base, cand []Outcome) (*Comparison, error) {
+	if len(base) != len(cand) {
+		return nil, fmt.Errorf("found %d baseline outcomes and %d candidate outcomes", len(base), len(cand))
+	}
+	cmp := &Comparison{
+		Baseline:  baseline,
+		Candidate: candidate,
+		WinDelta:  candidate.Pct - baseline.Pct,
+		MeanDelta: candidate.Mean - baseline.Mean,
+		Wins:      []string{},
+		Losses:    []string{},
+	}
+	for i := range base {
+		if base[i].Secret != cand[i].Secret {
+			return nil, fmt.Errorf("mismatched secrets `%s` and `%s`", base[i].Secret, cand[i].Secret)
+		}
+		switch x, y := base[i].rank(), cand[i].rank(); {
+		case y < x:
+			cmp.Wins = append(cmp.Wins, cand[i].Secret)
+		case y > x:
+			cmp.Losses = append(cmp.Losses, cand[i].Secret)
+		default:
+			cmp.Ties++
+		}
+	}
+	cmp.PValue = SignTest(len(cmp.Wins), len(cmp.Losses))
+	return cmp, nil
+}
+
+func readBaseline(filename string) (*Baseline, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	var baseline Baseline
+	if err = json.NewDecoder(fp).Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline `%s`: %w", filename, err)
+	}
+	return &baseline, nil
+}
+
+func writeBaseline(filename string, baseline *Baseline) error {
+	fp, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	enc := json.NewEncoder(fp)
+	enc.SetIndent("", " ")
+	return enc.Encode(baseline)
+}
+
+// sameFile returns true if both names refer to the same file, existing or not
+func sameFile(x, y string) bool {
+	fx, errx := os.Stat(x)
+	fy, erry := os.Stat(y)
+	if errx == nil && erry == nil {
+		return os.SameFile(fx, fy)
+	}
+	ax, errx := filepath.Abs(x)
+	ay, erry := filepath.Abs(y)
+	return errx == nil && erry == nil && ax == ay
+}
+
+func compare(c *cli.Context) error {
+	defer func(t time.Time) {
+		log.Info().Dur("elapsed", time.Since(t)).Msg(c.Command.Name)
+	}(time.Now())
+
+	var err error
+	var baseline *Baseline
+	configs := c.StringSlice("config")
+	switch {
+	case c.IsSet("baseline"):
+		if len(configs) != 1 {
+			return errors.New("expected one configuration to compare with the baseline")
+		}
+		if c.IsSet("save") && sameFile(c.Path("save"), c.Path("baseline")) {
+			return fmt.Errorf("refusing to save over the baseline `%s`", c.Path("baseline"))
+		}
+		baseline, err = readBaseline(c.Path("baseline"))
+		if err != nil {
+			return err
+		}
+		configs = []string{baseline.Config, configs[0]}
+	case c.IsSet("save"):
+		if len(configs) != 1 && len(configs) != 2 {
+			return errors.New("expected one or two configurations")
+		}
+	default:
+		if len(configs) != 2 {
+			return errors.New("expected two configurations")
+		}
+	}
+
+	// the secrets are recorded in the baseline
+	var secrets []string
+	switch baseline {
+	case nil:
+		secrets, err = sample(c)
+		if err != nil {
+			return err
+		}
+	default:
+		secrets = make([]string, len(baseline.Outcomes))
+		for i := range baseline.Outcomes {
+			secrets[i] = baseline.Outcomes[i].Secret
+		}
+	}
+
+	// only play the candidate if the baseline was recorded
+	played := configs
+	if baseline != nil {
+		played = configs[1:]
+	}
+	contenders, outcomes, err := contest(c, played, secrets)
+	if err != nil {
+		return err
+	}
+	if baseline == nil {
+		baseline = &Baseline{
+			Config:   contenders[0].config,
+			Strategy: contenders[0].strategy,
+			Secrets:  c.String("secrets"),
+			Sample:   c.Int("sample"),
+			Seed:     c.Uint64("seed"),
+			Outcomes: outcomes[0],
+		}
+		contenders, outcomes = contenders[1:], outcomes[1:]
+		if c.IsSet("save") {
+			if err = saveBaseline(c, baseline); err != nil {
+				return err
+			}
+		}
+	}
+	if len(contenders) == 0 {
+		return Runtime(c).Encoder.Encode(benchmark(baseline.Config, baseline.Strategy, baseline.Outcomes))
+	}
+
+	cmp, err := Compare(
+		benchmark(baseline.Config, baseline.Strategy, baseline.Outcomes),
+		benchmark(contenders[0].config, contenders[0].strategy, outcomes[0]),
+		baseline.Outcomes, outcomes[0])
+	if err != nil {
+		return err
+	}
+	threshold, means := c.Float64("threshold"), c.Float64("mean-threshold")
+	cmp.Regressed = cmp.WinDelta < -threshold || cmp.MeanDelta > means
+	if err = Runtime(c).Encoder.Encode(cmp); err != nil {
+		return err
+	}
+	switch {
+	case !c.IsSet("baseline"):
+		return nil
+	case cmp.WinDelta < -threshold:
+		return fmt.Errorf("win percentage regressed by %0.2f exceeding the threshold of %0.2f",
+			cmp.Baseline.Pct-cmp.Candidate.Pct, threshold)
+	case cmp.MeanDelta > means:
+		return fmt.Errorf("mean guesses regressed by %0.2f exceeding the threshold of %0.2f",
+			cmp.MeanDelta, means)
+	case c.IsSet("save"):
+		// a candidate which did not regress becomes the new baseline for the same secrets
+		return saveBaseline(c, &Baseline{
+			Config:   contenders[0].config,
+			Strategy: contenders[0].strategy,
+			Secrets:  baseline.Secrets,
+			Sample:   baseline.Sample,
+			Seed:     baseline.Seed,
+			Outcomes: outcomes[0],
+		})
+	}
+	return nil
+}
+
+// saveBaseline writes the baseline to the file named by the `save` flag
+func saveBaseline(c *cli.Context, baseline *Baseline) error {
+	if err := writeBaseline(c.Path("save"), baseline); err != nil {
+		return err
+	}
+	log.Info().Str("baseline", c.Path("save")).Str("config", baseline.Config).Msg("saved")
+	return nil
+}
+
+func CommandCompare() *cli.Command {
+	return &cli.Command{
+		Name:     "compare",
+		Category: categoryWordle,
+		Usage:    "Compare two strategy configurations by playing the same secrets",
+		Description: "Play the same seeded secrets with a baseline and a candidate configuration " +
+			"and report the paired differences. The baseline can be saved and later used to " +
+			"check a candidate, failing if the win percentage or the mean guesses regress beyond " +
+			"their thresholds.",
+		Flags: append(
+			[]cli.Flag{
+				&cli.PathFlag{
+					Name:  "baseline",
+					Usage: "compare the configuration with the recorded baseline",
+				},
+				&cli.PathFlag{
+					Name: "save",
+					Usage: "save the outcome of the first configuration as a baseline, or of the " +
+						"candidate if it does not regress from the baseline",
+				},
+				&cli.Float64Flag{
+					Name:  "threshold",
+					Usage: "maximum decrease in win percentage before failing the comparison with a baseline",
+					Value: 0,
+				},
+				&cli.Float64Flag{
+					Name:  "mean-threshold",
+					Usage: "maximum increase in mean guesses before failing the comparison with a baseline",
+					Value: 0.05,
+				},
+			},
+			benchFlags()...,
+		),
+		Action: compare,
+	}
+}
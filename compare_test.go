@@ -0,0 +1,221 @@
+package qordle_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestSignTest(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name         string
+		wins, losses int
+		p            float64
+	}{
+		{name: "no differences", p: 1},
+		{name: "balanced", wins: 3, losses: 3, p: 1},
+		{name: "all wins", wins: 10, p: 2.0 / 1024},
+		{name: "all losses", losses: 10, p: 2.0 / 1024},
+		{name: "mostly wins", wins: 7, losses: 3, p: 2 * 176.0 / 1024},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			a.InDelta(tt.p, qordle.SignTest(tt.wins, tt.losses), 1e-9)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	base := []qordle.Outcome{
+		{Secret: "brain", Rounds: 4, Success: true},
+		{Secret: "raise", Rounds: 3, Success: true},
+		{Secret: "sport", Rounds: 15, Success: false},
+		{Secret: "maths", Rounds: 5, Success: true},
+	}
+	cand := []qordle.Outcome{
+		{Secret: "brain", Rounds: 3, Success: true},
+		{Secret: "raise", Rounds: 3, Success: true},
+		{Secret: "sport", Rounds: 9, Success: true},
+		{Secret: "maths", Rounds: 15, Success: false},
+	}
+	baseline := &qordle.Benchmark{Pct: 75, Mean: 6.75}
+	candidate := &qordle.Benchmark{Pct: 50, Mean: 7.5}
+	cmp, err := qordle.Compare(baseline, candidate, base, cand)
+	a.NoError(err)
+	a.Equal([]string{"brain", "sport"}, cmp.Wins)
+	a.Equal([]string{"maths"}, cmp.Losses)
+	a.Equal(1, cmp.Ties)
+	a.InDelta(-25, cmp.WinDelta, 1e-9)
+	a.InDelta(0.75, cmp.MeanDelta, 1e-9)
+	a.InDelta(1, cmp.PValue, 1e-9)
+
+	_, err = qordle.Compare(baseline, candidate, base, cand[1:])
+	a.EqualError(err, "found 4 baseline outcomes and 3 candidate outcomes")
+	_, err = qordle.Compare(baseline, candidate, base[1:], cand[:3])
+	a.EqualError(err, "mismatched secrets `raise` and `brain`")
+}
+
+func TestCompareCommand(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "baseline.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	a.NoError(os.WriteFile(invalid, []byte("{"), 0o600))
+
+	decode := func(c *cli.Context) *qordle.Comparison {
+		var res qordle.Comparison
+		dec := json.NewDecoder(c.App.Writer.(io.Reader))
+		a.NoError(dec.Decode(&res))
+		return &res
+	}
+
+	for _, tt := range []harness{
+		{
+			name: "two configurations",
+			args: []string{"compare", "-n", "8", "-c", "-s pos --start soare", "-c", "-S -s freq"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("-s pos --start soare", res.Baseline.Config)
+				a.Equal("speculate{frequency}", res.Candidate.Strategy)
+				a.Equal(8, len(res.Wins)+len(res.Losses)+res.Ties)
+				a.InDelta(res.Candidate.Pct-res.Baseline.Pct, res.WinDelta, 1e-9)
+				a.Equal(res.WinDelta < 0 || res.MeanDelta > 0, res.Regressed)
+				return nil
+			},
+		},
+		{
+			name: "save a baseline",
+			args: []string{"compare", "-n", "8", "--seed", "5", "--save", baseline, "-c", "-s pos --start soare"},
+			after: func(c *cli.Context) error {
+				var res qordle.Benchmark
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal(8, res.Games)
+				fp, err := os.Open(baseline)
+				a.NoError(err)
+				defer fp.Close()
+				var b qordle.Baseline
+				a.NoError(json.NewDecoder(fp).Decode(&b))
+				a.Equal("-s pos --start soare", b.Config)
+				a.Equal(uint64(5), b.Seed)
+				a.Len(b.Outcomes, 8)
+				return nil
+			},
+		},
+		{
+			name: "compare with the baseline",
+			args: []string{"compare", "--baseline", baseline, "-c", "-s pos --start soare"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal(8, res.Ties)
+				a.Empty(res.Wins)
+				a.Empty(res.Losses)
+				a.False(res.Regressed)
+				a.InDelta(1, res.PValue, 1e-9)
+				return nil
+			},
+		},
+		{
+			name: "regression from the baseline",
+			args: []string{"compare", "--baseline", baseline, "--threshold", "-1", "-c", "-s pos --start soare"},
+			err:  "win percentage regressed by 0.00 exceeding the threshold of -1.00",
+		},
+		{
+			name: "mean regression from the baseline",
+			args: []string{
+				"compare", "--baseline", baseline, "--threshold", "100", "--mean-threshold", "-1",
+				"-c", "-s pos --start soare",
+			},
+			err: "mean guesses regressed by 0.00 exceeding the threshold of -1.00",
+		},
+		{
+			name: "save over the baseline",
+			args: []string{"compare", "--baseline", baseline, "--save", dir + "/./baseline.json", "-c", "-s pos"},
+			err:  "refusing to save over the baseline",
+		},
+		{
+			name: "save the candidate as a new baseline",
+			args: []string{
+				"compare", "--baseline", baseline, "--save", filepath.Join(dir, "candidate.json"),
+				"--threshold", "100", "--mean-threshold", "100", "-c", "-s pos",
+			},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("-s pos --start soare", res.Baseline.Config)
+				fp, err := os.Open(filepath.Join(dir, "candidate.json"))
+				a.NoError(err)
+				defer fp.Close()
+				var b qordle.Baseline
+				a.NoError(json.NewDecoder(fp).Decode(&b))
+				a.Equal("-s pos", b.Config)
+				a.Equal(uint64(5), b.Seed)
+				a.Len(b.Outcomes, 8)
+				return nil
+			},
+		},
+		{
+			name: "refuse to save a regressed candidate",
+			args: []string{
+				"compare", "--baseline", baseline, "--save", filepath.Join(dir, "regressed.json"),
+				"--threshold", "100", "--mean-threshold", "-100", "-c", "-s pos",
+			},
+			err: "mean guesses regressed",
+			after: func(c *cli.Context) error {
+				a.NoFileExists(filepath.Join(dir, "regressed.json"))
+				return nil
+			},
+		},
+		{
+			name: "missing configuration for the baseline",
+			args: []string{"compare", "--baseline", baseline},
+			err:  "expected one configuration to compare with the baseline",
+		},
+		{
+			name: "missing baseline",
+			args: []string{"compare", "--baseline", filepath.Join(dir, "missing.json"), "-c", "-s pos"},
+			err:  "no such file or directory",
+		},
+		{
+			name: "invalid baseline",
+			args: []string{"compare", "--baseline", invalid, "-c", "-s pos"},
+			err:  "invalid baseline",
+		},
+		{
+			name: "one configuration",
+			args: []string{"compare", "-c", "-s pos"},
+			err:  "expected two configurations",
+		},
+		{
+			name: "too many configurations to save",
+			args: []string{"compare", "--save", baseline, "-c", "-s pos", "-c", "-s freq", "-c", "-s alpha"},
+			err:  "expected one or two configurations",
+		},
+		{
+			name: "invalid secrets",
+			args: []string{"compare", "--secrets", "foobar", "-c", "-s pos", "-c", "-s freq"},
+			err:  "invalid wordlist `foobar`",
+		},
+		{
+			name: "invalid configuration",
+			args: []string{"compare", "-c", "-s pos", "-c", "-s foobar"},
+			err:  "unknown strategy `foobar`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandCompare)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package qordle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// dailyURL is the default location of the daily puzzle, `{date}` is replaced with the date
+const dailyURL = "https://www.nytimes.com/svc/wordle/v2/{date}.json"
+
+// Daily is the metadata of the puzzle for a date
+type Daily struct {
+	ID       int    `json:"id"`
+	Solution string `json:"solution"`
+	Date     string `json:"print_date"`
+	Number   int    `json:"days_since_launch"`
+	Editor   string `json:"editor,omitempty"`
+}
+
+func dailyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "url",
+			Usage: "url of the daily puzzle with `{date}` replaced by the date",
+			Value: dailyURL,
+		},
+		&cli.PathFlag{
+			Name:  "cache",
+			Usage: "directory caching the daily puzzles, the user cache directory if not specified",
+		},
+		&cli.BoolFlag{
+			Name:  "refresh",
+			Usage: "fetch the daily puzzle even if cached",
+		},
+	}
+}
+
+// day returns the date of the `date` flag, either `today` or YYYY-MM-DD
+func day(c *cli.Context) (time.Time, error) {
+	switch s := c.String("date"); s {
+	case "", "today":
+		return Runtime(c).Start, nil
+	default:
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date `%s`", s)
+		}
+		return t, nil
+	}
+}
+
+// cache returns the directory for caching the daily puzzles or the empty string if unavailable
+func cache(c *cli.Context) string {
+	if dir := c.Path("cache"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Warn().Err(err).Msg("cache")
+		return ""
+	}
+	return filepath.Join(dir, "qordle")
+}
+
+func readDaily(filename string) (*Daily, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	var d Daily
+	if err = json.NewDecoder(fp).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func writeDaily(filename string, d *Daily) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	fp, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return json.NewEncoder(fp).Encode(d)
+}
+
+// fetch the daily puzzle for the date
+func fetch(c *cli.Context, date time.Time) (*Daily, error) {
+	grab := Runtime(c).Grab
+	if grab == nil {
+		return nil, errors.New("missing http client")
+	}
+	url := strings.ReplaceAll(c.String("url"), "{date}", date.Format(time.DateOnly))
+	req, err := http.NewRequestWithContext(c.Context, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := grab.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	var d Daily
+	if err = json.NewDecoder(res.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	if d.Solution == "" {
+		return nil, errors.New("missing solution")
+	}
+	d.Solution = strings.ToLower(d.Solution)
+	return &d, nil
+}
+
+// daily returns the puzzle for the `date` flag from the cache or, if not cached, fetched
+func daily(c *cli.Context) (*Daily, error) {
+	date, err := day(c)
+	if err != nil {
+		return nil, err
+	}
+	var filename string
+	if dir := cache(c); dir != "" {
+		// puzzles of the same date from different urls are cached separately
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(c.String("url")))
+		filename = filepath.Join(dir, fmt.Sprintf("daily-%s-%08x.json", date.Format(time.DateOnly), h.Sum32()))
+	}
+	if filename != "" && !c.Bool("refresh") {
+		if d, err := readDaily(filename); err == nil {
+			log.Debug().Str("filename", filename).Msg("cached")
+			return d, nil
+		}
+	}
+	d, err := fetch(c, date)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the daily puzzle for %s: %w", date.Format(time.DateOnly), err)
+	}
+	if filename != "" {
+		if err = writeDaily(filename, d); err != nil {
+			// the puzzle is still usable if caching fails
+			log.Warn().Err(err).Str("filename", filename).Msg("cache")
+		}
+	}
+	return d, nil
+}
+
+func CommandDaily() *cli.Command {
+	return &cli.Command{
+		Name:     "daily",
+		Category: categoryWordle,
+		Usage:    "Fetch the daily puzzle",
+		Description: "Fetch the metadata, including the solution, of the daily puzzle for today or " +
+			"the date, caching the result locally so later uses work offline.",
+		Flags: append(
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:  "date",
+					Usage: "date of the puzzle, `today` or YYYY-MM-DD",
+					Value: "today",
+				},
+			},
+			dailyFlags()...,
+		),
+		Action: func(c *cli.Context) error {
+			d, err := daily(c)
+			if err != nil {
+				return err
+			}
+			return Runtime(c).Encoder.Encode(d)
+		},
+	}
+}
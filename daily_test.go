@@ -0,0 +1,269 @@
+package qordle_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func newDailyServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/{date}", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.PathValue("date") {
+		case "2024-06-01.json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1,"solution":"TABLE","print_date":"2024-06-01","days_since_launch":1078}`))
+		case "2024-06-02.json":
+			_, _ = w.Write([]byte(`{"id":2}`))
+		case "2024-06-03.json":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDailyCommand(t *testing.T) {
+	a := assert.New(t)
+
+	var requests atomic.Int32
+	srv := newDailyServer(t, &requests)
+	url := srv.URL + "/v2/{date}.json"
+	grab := func(c *cli.Context) error {
+		qordle.Runtime(c).Grab = srv.Client()
+		return nil
+	}
+	decode := func(c *cli.Context) *qordle.Daily {
+		var res qordle.Daily
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return &res
+	}
+
+	dir := t.TempDir()
+	for _, tt := range []harness{
+		{
+			name:   "fetch",
+			args:   []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-01"},
+			before: grab,
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("table", res.Solution)
+				a.Equal(1078, res.Number)
+				a.Equal("2024-06-01", res.Date)
+				a.Equal(int32(1), requests.Load())
+				cached, err := filepath.Glob(filepath.Join(dir, "daily-2024-06-01-*.json"))
+				a.NoError(err)
+				a.Len(cached, 1)
+				return nil
+			},
+		},
+		{
+			name: "cached without a client",
+			args: []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-01"},
+			after: func(c *cli.Context) error {
+				a.Equal("table", decode(c).Solution)
+				a.Equal(int32(1), requests.Load())
+				return nil
+			},
+		},
+		{
+			name: "cached by url",
+			args: []string{"daily", "--url", srv.URL + "/v3/{date}.json", "--cache", dir, "--date", "2024-06-01"},
+			err:  "unable to fetch the daily puzzle for 2024-06-01: missing http client",
+		},
+		{
+			name:   "refresh the cache",
+			args:   []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-01", "--refresh"},
+			before: grab,
+			after: func(c *cli.Context) error {
+				a.Equal("table", decode(c).Solution)
+				a.Equal(int32(2), requests.Load())
+				return nil
+			},
+		},
+		{
+			name: "today",
+			args: []string{"daily", "--url", url, "--cache", dir},
+			before: func(c *cli.Context) error {
+				qordle.Runtime(c).Start = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				a.Equal("2024-06-01", decode(c).Date)
+				return nil
+			},
+		},
+		{
+			name: "offline",
+			args: []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-04"},
+			err:  "unable to fetch the daily puzzle for 2024-06-04: missing http client",
+		},
+		{
+			name:   "not found",
+			args:   []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-04"},
+			before: grab,
+			err:    "unable to fetch the daily puzzle for 2024-06-04: unexpected status 404 Not Found",
+		},
+		{
+			name:   "missing solution",
+			args:   []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-02"},
+			before: grab,
+			err:    "unable to fetch the daily puzzle for 2024-06-02: missing solution",
+		},
+		{
+			name:   "invalid response",
+			args:   []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-03"},
+			before: grab,
+			err:    "unable to fetch the daily puzzle for 2024-06-03: invalid character",
+		},
+		{
+			name: "unreachable",
+			args: []string{"daily", "--url", "http://127.0.0.1:0/{date}", "--cache", dir, "--date", "2024-06-05"},
+			before: func(c *cli.Context) error {
+				qordle.Runtime(c).Grab = &http.Client{Timeout: time.Second}
+				return nil
+			},
+			err: "unable to fetch the daily puzzle for 2024-06-05",
+		},
+		{
+			name: "invalid date",
+			args: []string{"daily", "--cache", dir, "--date", "yesterday"},
+			err:  "invalid date `yesterday`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandDaily)
+		})
+	}
+}
+
+func TestDailyCache(t *testing.T) {
+	a := assert.New(t)
+
+	var requests atomic.Int32
+	srv := newDailyServer(t, &requests)
+	url := srv.URL + "/v2/{date}.json"
+
+	// the puzzle is usable even if it cannot be cached
+	dir := filepath.Join(t.TempDir(), "file")
+	a.NoError(os.WriteFile(dir, nil, 0o600))
+	tt := &harness{
+		name: "uncacheable",
+		args: []string{"daily", "--url", url, "--cache", dir, "--date", "2024-06-01"},
+		before: func(c *cli.Context) error {
+			qordle.Runtime(c).Grab = srv.Client()
+			return nil
+		},
+		after: func(c *cli.Context) error {
+			var res qordle.Daily
+			a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+			a.Equal("table", res.Solution)
+			return nil
+		},
+	}
+	run(t, tt, qordle.CommandDaily)
+}
+
+func TestDailyPlayAnalyze(t *testing.T) {
+	a := assert.New(t)
+
+	var requests atomic.Int32
+	srv := newDailyServer(t, &requests)
+	url := srv.URL + "/v2/{date}.json"
+	grab := func(c *cli.Context) error {
+		qordle.Runtime(c).Grab = srv.Client()
+		return nil
+	}
+
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		harness
+		cmd func() *cli.Command
+	}{
+		{
+			cmd: qordle.CommandPlay,
+			harness: harness{
+				name:   "play the daily puzzle",
+				args:   []string{"play", "--start", "soare", "--url", url, "--cache", dir, "--date", "2024-06-01"},
+				before: grab,
+				after: func(c *cli.Context) error {
+					var res qordle.Scoreboard
+					a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+					a.Equal("table", res.Target)
+					a.Equal(&qordle.Selection{From: "daily", Date: "2024-06-01"}, res.Selection)
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandPlay,
+			harness: harness{
+				name: "play the daily puzzle with a sample",
+				args: []string{"play", "--url", url, "--cache", dir, "--date", "2024-06-01", "--sample", "2"},
+				err:  "the daily puzzle cannot be combined with other selections",
+			},
+		},
+		{
+			cmd: qordle.CommandPlay,
+			harness: harness{
+				name: "play the daily puzzle offline",
+				args: []string{"play", "--url", url, "--cache", dir, "--date", "2024-06-04"},
+				err:  "unable to fetch the daily puzzle for 2024-06-04",
+			},
+		},
+		{
+			cmd: qordle.CommandAnalyze,
+			harness: harness{
+				name:   "analyze the daily puzzle",
+				args:   []string{"analyze", "--url", url, "--cache", dir, "--date", "2024-06-01", "soare", "table"},
+				before: grab,
+				after: func(c *cli.Context) error {
+					var res qordle.Analysis
+					a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+					a.Equal("table", res.Secret)
+					a.True(res.Solved)
+					a.Len(res.Reviews, 2)
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandAnalyze,
+			harness: harness{
+				name: "analyze the daily puzzle without guesses",
+				args: []string{"analyze", "--date", "2024-06-01"},
+				err:  "expected at least one guess",
+			},
+		},
+		{
+			cmd: qordle.CommandAnalyze,
+			harness: harness{
+				name: "analyze the daily puzzle offline",
+				args: []string{"analyze", "--url", url, "--cache", dir, "--date", "2024-06-04", "soare"},
+				err:  "unable to fetch the daily puzzle for 2024-06-04",
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt.harness, tt.cmd)
+		})
+	}
+}
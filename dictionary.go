@@ -2,20 +2,35 @@ package qordle
 
 import (
 	"bufio"
+	"compress/gzip"
 	"embed"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/urfave/cli/v2"
 )
 
 type Dictionary []string
 
+func (d Dictionary) Header() []string {
+	return []string{"word"}
+}
+
+func (d Dictionary) Rows() [][]string {
+	rows := make([][]string, len(d))
+	for i := range d {
+		rows[i] = []string{d[i]}
+	}
+	return rows
+}
+
 const data = "data"
 
 //go:embed data
@@ -41,6 +56,51 @@ func (dict Dictionary) union(other Dictionary) Dictionary {
 	return dictionary
 }
 
+// set of the words in the dictionary
+func (dict Dictionary) set() map[string]bool {
+	words := make(map[string]bool, len(dict))
+	for i := range dict {
+		words[dict[i]] = true
+	}
+	return words
+}
+
+// Intersect returns the words also in the other dictionary, in order
+func (dict Dictionary) Intersect(other Dictionary) Dictionary {
+	words := other.set()
+	var res Dictionary
+	for i := range dict {
+		if words[dict[i]] {
+			res = append(res, dict[i])
+		}
+	}
+	return res
+}
+
+// Difference returns the words not in the other dictionary, in order
+func (dict Dictionary) Difference(other Dictionary) Dictionary {
+	words := other.set()
+	var res Dictionary
+	for i := range dict {
+		if !words[dict[i]] {
+			res = append(res, dict[i])
+		}
+	}
+	return res
+}
+
+// Exclude returns the words not matching any of the excluded words regardless of case, in order
+func (dict Dictionary) Exclude(words ...string) Dictionary {
+	if len(words) == 0 {
+		return dict
+	}
+	excluded := make(Dictionary, len(words))
+	for i := range words {
+		excluded[i] = strings.ToLower(words[i])
+	}
+	return dict.Difference(excluded)
+}
+
 func read(r io.Reader) (Dictionary, error) {
 	if r == nil {
 		return nil, errors.New("invalid reader")
@@ -68,6 +128,45 @@ func Read(name string) (Dictionary, error) {
 	return read(fp)
 }
 
+// readRaw reads the lines of an external word list, decompressing it if gzipped
+func readRaw(r io.Reader) (Dictionary, error) {
+	if r == nil {
+		return nil, errors.New("invalid reader")
+	}
+	br := bufio.NewReader(r)
+	r = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return read(r)
+}
+
+// ReadWordlist reads an external word list, decompressing it if gzipped, normalizing
+// the words by trimming and lowercasing them and skipping duplicates, blank lines and
+// lines starting with `#`
+func ReadWordlist(r io.Reader) (Dictionary, error) {
+	lines, err := readRaw(r)
+	if err != nil {
+		return nil, err
+	}
+	var res Dictionary
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		word := strings.ToLower(strings.TrimSpace(line))
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+		seen[word] = true
+		res = append(res, word)
+	}
+	return res, nil
+}
+
 func wordlistFlags() []cli.Flag {
 	return []cli.Flag{
 		&cli.StringSliceFlag{
@@ -75,39 +174,117 @@ func wordlistFlags() []cli.Flag {
 			Aliases: []string{"w"},
 			Usage:   "use the specified embedded word list",
 		},
-		// &cli.StringSliceFlag{
-		// 	Name:    "Wordlist",
-		// 	Aliases: []string{"W"},
-		// 	Usage:   "use the specified external word list",
-		// },
+		&cli.StringSliceFlag{
+			Name:    "Wordlist",
+			Aliases: []string{"W"},
+			Usage:   "use the specified external word list, `-` for stdin, optionally gzipped",
+		},
+		&cli.StringSliceFlag{
+			Name:    "intersect",
+			Aliases: []string{"i"},
+			Usage:   "keep only the words also in the embedded or external word list",
+		},
+	}
+}
+
+// exclusionFlags are the flags for the words which cannot be the answer, such as previous answers
+func exclusionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "exclude",
+			Aliases: []string{"x"},
+			Usage:   "exclude the words of an embedded or external word list, `-` for stdin, or the word itself",
+		},
+		&cli.PathFlag{
+			Name:    "history",
+			Usage:   "exclude the previous answers recorded in the history file",
+			EnvVars: []string{"QORDLE_HISTORY"},
+		},
 	}
 }
 
+// lookup the word list as `-` for stdin, an embedded word list or else an external file, in that
+// order, so a file in the working directory never shadows an embedded word list, reading stdin
+// and external files with the reader
+func lookup(c *cli.Context, wordlist string, reader func(io.Reader) (Dictionary, error)) (Dictionary, error) {
+	if wordlist != "-" {
+		if words, err := Read(wordlist); err == nil {
+			return words, nil
+		}
+	}
+	return external(c, wordlist, reader)
+}
+
+// external reads the word list as `-` for stdin or else a file with the reader, never an embedded
+// word list
+func external(c *cli.Context, wordlist string, reader func(io.Reader) (Dictionary, error)) (Dictionary, error) {
+	if wordlist == "-" {
+		return reader(c.App.Reader)
+	}
+	fp, err := os.Open(wordlist)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("invalid wordlist `%s`", wordlist)
+		}
+		return nil, err
+	}
+	defer fp.Close()
+	return reader(fp)
+}
+
+// resolve the word list, normalizing the words of stdin and external files
+func resolve(c *cli.Context, wordlist string) (Dictionary, error) {
+	return lookup(c, wordlist, ReadWordlist)
+}
+
+// excludesWord returns true if the exclusion is a word rather than stdin, an embedded word list or
+// an existing file, so a mistyped file name is still reported as an invalid word list
+func excludesWord(exclude string) bool {
+	if exclude == "" || strings.IndexFunc(exclude, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return false
+	}
+	if _, err := fs.Stat(dataFs, fmt.Sprintf("%s/%s.txt", data, exclude)); err == nil {
+		return false
+	}
+	_, err := os.Stat(exclude)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// exclusions returns the words to exclude from the `exclude` and `history` flags
+func exclusions(c *cli.Context) (Dictionary, error) {
+	var res Dictionary
+	for _, exclude := range c.StringSlice("exclude") {
+		if excludesWord(exclude) {
+			res = append(res, strings.ToLower(exclude))
+			continue
+		}
+		words, err := resolve(c, exclude)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, words...)
+	}
+	history, err := ReadHistory(c.Path("history"))
+	if err != nil {
+		return nil, err
+	}
+	return append(res, history...), nil
+}
+
 func wordlists(c *cli.Context, wordlists ...string) (Dictionary, error) {
 	var readers []func() (Dictionary, error)
-	switch {
-	// case c.IsSet("Wordlist"):
-	// 	for _, wordlist := range c.StringSlice("Wordlist") {
-	// 		w := wordlist
-	// 		readers = append(readers, func() (Dictionary, error) {
-	// 			fp, err := os.Open(w)
-	// 			if err != nil {
-	// 				return nil, err
-	// 			}
-	// 			defer fp.Close()
-	// 			return read(fp)
-	// 		})
-	// 	}
-	case c.IsSet("wordlist"):
+	if c.IsSet("wordlist") || c.IsSet("Wordlist") {
 		wordlists = c.StringSlice("wordlist")
-		fallthrough
-	default:
-		for _, wordlist := range wordlists {
-			w := wordlist
-			readers = append(readers, func() (Dictionary, error) {
-				return Read(w)
-			})
-		}
+	}
+	for _, wordlist := range wordlists {
+		readers = append(readers, func() (Dictionary, error) {
+			return Read(wordlist)
+		})
+	}
+	for _, wordlist := range c.StringSlice("Wordlist") {
+		readers = append(readers, func() (Dictionary, error) {
+			return external(c, wordlist, ReadWordlist)
+		})
 	}
 	var words Dictionary
 	for _, reader := range readers {
@@ -117,29 +294,59 @@ func wordlists(c *cli.Context, wordlists ...string) (Dictionary, error) {
 		}
 		words = words.union(res)
 	}
+	for _, intersect := range c.StringSlice("intersect") {
+		res, err := resolve(c, intersect)
+		if err != nil {
+			return nil, err
+		}
+		words = words.Intersect(res)
+	}
 	return words, nil
 }
 
+// excluding removes the words excluded by the `exclude` and `history` flags from the dictionary
+func excluding(c *cli.Context, dictionary Dictionary) (Dictionary, error) {
+	excluded, err := exclusions(c)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary.Difference(excluded), nil
+}
+
+// embedded returns the sorted names of the embedded word lists
+func embedded() ([]string, error) {
+	var lists []string
+	if err := fs.WalkDir(dataFs, data, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		lists = append(lists, strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(lists)
+	return lists, nil
+}
+
 func CommandWordlists() *cli.Command {
 	return &cli.Command{
 		Name:     "wordlists",
 		Category: categoryWordle,
 		Usage:    "List all available wordlists",
+		Subcommands: []*cli.Command{
+			commandWordlistsShow(),
+			commandWordlistsStats(),
+			commandWordlistsDiff(),
+		},
 		Action: func(c *cli.Context) error {
-			var lists []string
-			if err := fs.WalkDir(dataFs, data, func(_ string, d fs.DirEntry, err error) error {
-				if err != nil {
-					return err
-				}
-				if d.IsDir() {
-					return nil
-				}
-				lists = append(lists, strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))
-				return nil
-			}); err != nil {
+			lists, err := embedded()
+			if err != nil {
 				return err
 			}
-			sort.Strings(lists)
 			return Runtime(c).Encoder.Encode(lists)
 		},
 	}
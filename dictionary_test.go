@@ -1,8 +1,13 @@
 package qordle_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,8 +50,350 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestDictionarySets(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dict := qordle.Dictionary{"table", "cable", "fable", "gable"}
+	other := qordle.Dictionary{"gable", "sable", "table"}
+	a.Equal(qordle.Dictionary{"table", "gable"}, dict.Intersect(other))
+	a.Equal(qordle.Dictionary{"cable", "fable"}, dict.Difference(other))
+	a.Equal(qordle.Dictionary{"cable", "gable"}, dict.Exclude("TABLE", "fable", "hable"))
+	a.Equal(dict, dict.Exclude())
+	a.Empty(dict.Intersect(nil))
+	a.Equal(dict, dict.Difference(nil))
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestReadWordlist(t *testing.T) {
+	t.Parallel()
+	const words = "# curated\n  Table\nCABLE\n\n\ttable \nfable\n# done\n"
+	for _, tt := range []struct {
+		name   string
+		reader io.Reader
+		words  qordle.Dictionary
+		err    string
+	}{
+		{
+			name:   "normalized",
+			reader: strings.NewReader(words),
+			words:  qordle.Dictionary{"table", "cable", "fable"},
+		},
+		{
+			name:   "gzipped",
+			reader: bytes.NewReader(gzipped(t, words)),
+			words:  qordle.Dictionary{"table", "cable", "fable"},
+		},
+		{
+			name:   "empty",
+			reader: strings.NewReader(""),
+		},
+		{
+			name:   "truncated gzip",
+			reader: bytes.NewReader(gzipped(t, words)[:12]),
+			err:    "unexpected EOF",
+		},
+		{
+			name: "nil reader",
+			err:  "invalid reader",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			dictionary, err := qordle.ReadWordlist(tt.reader)
+			if tt.err != "" {
+				a.ErrorContains(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.words, dictionary)
+		})
+	}
+}
+
+func TestHistoryExcludesCandidatesOnly(t *testing.T) {
+	a := assert.New(t)
+	history := filepath.Join(t.TempDir(), "history.txt")
+	a.NoError(os.WriteFile(history, []byte("table\n"), 0o600))
+	t.Setenv("QORDLE_HISTORY", history)
+	for _, tt := range []struct {
+		harness
+		cmd func() *cli.Command
+	}{
+		{
+			harness: harness{
+				name: "suggest excludes the history",
+				args: []string{"suggest", "-s", "alpha", "-w", "solutions", "tABLE"},
+				after: func(c *cli.Context) error {
+					var res []string
+					a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+					a.NotContains(res, "table")
+					a.Contains(res, "cable")
+					return nil
+				},
+			},
+			cmd: qordle.CommandSuggest,
+		},
+		{
+			harness: harness{
+				name: "analyze keeps the history",
+				args: []string{"analyze", "-w", "solutions", "table", "soare", "table"},
+				after: func(c *cli.Context) error {
+					var res qordle.Analysis
+					a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+					a.True(res.Solved)
+					a.Equal(1, res.Reviews[1].After)
+					return nil
+				},
+			},
+			cmd: qordle.CommandAnalyze,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt.harness, tt.cmd)
+		})
+	}
+}
+
+func TestShadowedWordlists(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	a.NoError(os.WriteFile(filepath.Join(dir, "solutions"), []byte("zzzzz\n"), 0o600))
+	t.Chdir(dir)
+	decode := func(c *cli.Context) []string {
+		var res []string
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return res
+	}
+	for _, tt := range []struct {
+		harness
+		cmd func() *cli.Command
+	}{
+		{
+			harness: harness{
+				name: "embedded word list before a file of the same name",
+				args: []string{"suggest", "-s", "alpha", "-w", "solutions", "-i", "solutions", "--limit", "1", "fuzzy"},
+				after: func(c *cli.Context) error {
+					a.Equal([]string{"aback"}, decode(c))
+					return nil
+				},
+			},
+			cmd: qordle.CommandSuggest,
+		},
+		{
+			harness: harness{
+				name: "explicit path to the file",
+				args: []string{"suggest", "-s", "alpha", "-w", "solutions", "-i", "./solutions", "fuzzy"},
+				after: func(c *cli.Context) error {
+					a.Empty(decode(c))
+					return nil
+				},
+			},
+			cmd: qordle.CommandSuggest,
+		},
+		{
+			harness: harness{
+				name: "external word list is always a file",
+				args: []string{"suggest", "-s", "alpha", "-W", "solutions", "ZZZZZ"},
+				after: func(c *cli.Context) error {
+					a.Equal([]string{"zzzzz"}, decode(c))
+					return nil
+				},
+			},
+			cmd: qordle.CommandSuggest,
+		},
+		{
+			harness: harness{
+				name: "external word list is never embedded",
+				args: []string{"suggest", "-W", "possible", "fuzzy"},
+				err:  "invalid wordlist `possible`",
+			},
+			cmd: qordle.CommandSuggest,
+		},
+		{
+			harness: harness{
+				name: "show the embedded word list",
+				args: []string{"wordlists", "show", "solutions"},
+				after: func(c *cli.Context) error {
+					res := decode(c)
+					a.Contains(res, "aback")
+					a.NotContains(res, "zzzzz")
+					return nil
+				},
+			},
+			cmd: qordle.CommandWordlists,
+		},
+		{
+			harness: harness{
+				name: "show the file",
+				args: []string{"wordlists", "show", "./solutions"},
+				after: func(c *cli.Context) error {
+					a.Equal([]string{"zzzzz"}, decode(c))
+					return nil
+				},
+			},
+			cmd: qordle.CommandWordlists,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt.harness, tt.cmd)
+		})
+	}
+}
+
+func TestExternalWordlists(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "curated.txt")
+	a.NoError(os.WriteFile(plain, []byte("# curated\nRAPID\nrabid\nrapid\n"), 0o600))
+	compressed := filepath.Join(dir, "curated.txt.gz")
+	a.NoError(os.WriteFile(compressed, gzipped(t, "rajah\nbrain\n"), 0o600))
+	extra := filepath.Join(dir, "extra.txt")
+	a.NoError(os.WriteFile(extra, []byte("pshaw\nrapid\n"), 0o600))
+
+	decode := func(c *cli.Context) []string {
+		var res []string
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return res
+	}
+	for _, tt := range []harness{
+		{
+			name: "external file",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "external files including gzip",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "-W", compressed, "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"brain", "rabid", "rajah", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "stdin",
+			args: []string{"suggest", "-s", "alpha", "-W", "-", "fuzzy"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = bytes.NewReader(gzipped(t, "rapid\nrabid\n"))
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "mixed with embedded",
+			args: []string{"suggest", "-s", "alpha", "-w", "solutions", "-W", compressed, "fuzzy"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Contains(res, "rajah")
+				a.Contains(res, "rapid")
+				return nil
+			},
+		},
+		{
+			name: "missing file",
+			args: []string{"suggest", "-W", filepath.Join(dir, "missing.txt"), "fuzzy"},
+			err:  "invalid wordlist `" + filepath.Join(dir, "missing.txt") + "`",
+		},
+		{
+			name: "exclude word lists",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "-W", compressed, "-x", compressed, "-x", "-", "fuzzy"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader("RAPID\n")
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "exclude an unknown word list",
+			args: []string{"suggest", "-x", filepath.Join(dir, "mistyped.txt"), "fuzzy"},
+			err:  "invalid wordlist `" + filepath.Join(dir, "mistyped.txt") + "`",
+		},
+		{
+			name: "exclude a word",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "-x", "RABID", "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "exclude an embedded word list",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "-W", extra, "-x", "solutions", "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"pshaw"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "intersect",
+			args: []string{"suggest", "-s", "alpha", "-w", "possible", "-w", "solutions", "-i", plain, "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "intersect an invalid word list",
+			args: []string{"suggest", "-i", "foobar", "fuzzy"},
+			err:  "invalid wordlist `foobar`",
+		},
+		{
+			name: "exclude the history",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "-W", compressed, "--history", compressed, "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "missing history",
+			args: []string{"suggest", "-s", "alpha", "-W", plain, "--history", filepath.Join(dir, "missing.txt"), "fuzzy"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"rabid", "rapid"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "unreadable exclusion",
+			args: []string{"suggest", "-x", dir, "fuzzy"},
+			err:  "is a directory",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandSuggest)
+		})
+	}
+}
+
 func TestWordlistsCommand(t *testing.T) {
 	a := assert.New(t)
+	dir := t.TempDir()
+	audit := filepath.Join(dir, "audit.txt")
+	a.NoError(os.WriteFile(audit, []byte("table\ncable\nTable\ncafé\ntable\n"), 0o600))
+	older := filepath.Join(dir, "older.txt.gz")
+	a.NoError(os.WriteFile(older, gzipped(t, "cable\nfable\ntable\n"), 0o600))
 	for _, tt := range []harness{
 		{
 			name: "wordlists",
@@ -59,6 +406,88 @@ func TestWordlistsCommand(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "show",
+			args: []string{"wordlists", "show", audit},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"table", "cable", "Table", "café", "table"}, res)
+				return nil
+			},
+		},
+		{
+			name: "show missing wordlist",
+			args: []string{"wordlists", "show"},
+			err:  "missing wordlist",
+		},
+		{
+			name: "show invalid wordlist",
+			args: []string{"wordlists", "show", "nonexistent"},
+			err:  "invalid wordlist `nonexistent`",
+		},
+		{
+			name: "stats",
+			args: []string{"wordlists", "stats", audit, "solutions"},
+			after: func(c *cli.Context) error {
+				var res []*qordle.Stats
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Len(res, 2)
+				a.Equal(&qordle.Stats{
+					Name:       audit,
+					Words:      5,
+					Unique:     4,
+					Lengths:    map[int]int{4: 1, 5: 3},
+					Uppercase:  []string{"Table"},
+					NonASCII:   []string{"café"},
+					Duplicates: []string{"table"},
+					Overlap:    map[string]int{"solutions": 2},
+				}, res[0])
+				a.Equal(2309, res[1].Words)
+				a.Equal(map[int]int{5: 2309}, res[1].Lengths)
+				a.Empty(res[1].Duplicates)
+				a.Equal(map[string]int{audit: 2}, res[1].Overlap)
+				return nil
+			},
+		},
+		{
+			name: "stats embedded",
+			args: []string{"wordlists", "stats"},
+			after: func(c *cli.Context) error {
+				var res []*qordle.Stats
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Len(res, 3)
+				a.Equal("possible", res[0].Name)
+				a.Equal(0, res[0].Overlap["solutions"])
+				a.Equal(res[0].Unique, res[0].Overlap["qordle"])
+				return nil
+			},
+		},
+		{
+			name: "diff",
+			args: []string{"wordlists", "diff", older, audit},
+			after: func(c *cli.Context) error {
+				var res qordle.Diff
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal(qordle.Diff{
+					From:    older,
+					To:      audit,
+					Added:   []string{"Table", "café"},
+					Removed: []string{"fable"},
+					Common:  2,
+				}, res)
+				return nil
+			},
+		},
+		{
+			name: "diff missing wordlist",
+			args: []string{"wordlists", "diff", audit},
+			err:  "expected two wordlists",
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,242 @@
+package qordle
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats supported by NewEncoder
+var Formats = []string{"csv", "json", "ndjson", "table", "text", "yaml"}
+
+// Tabular is implemented by values with a tabular representation
+type Tabular interface {
+	// Header returns the names of the columns
+	Header() []string
+	// Rows returns the rows of the table
+	Rows() [][]string
+}
+
+// Flusher is implemented by encoders buffering their output
+type Flusher interface {
+	// Flush writes any buffered output
+	Flush() error
+}
+
+// EncoderOption provides a configuration mechanism for an Encoder
+type EncoderOption func(*encoding)
+
+type encoding struct {
+	tiles *Tiles
+}
+
+// WithTiles renders values supporting tiles in the text format
+func WithTiles(tiles *Tiles) EncoderOption {
+	return func(e *encoding) {
+		e.tiles = tiles
+	}
+}
+
+// NewEncoder creates an encoder writing the format to the writer
+func NewEncoder(w io.Writer, format string, opts ...EncoderOption) (Encoder, error) {
+	cfg := new(encoding)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	switch format {
+	case "json":
+		return json.NewEncoder(w), nil
+	case "ndjson":
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case "yaml":
+		return &yamlEncoder{enc: yaml.NewEncoder(w)}, nil
+	case "csv":
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case "table":
+		return &tableEncoder{w: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}, nil
+	case "text":
+		return &textEncoder{w: w, tiles: cfg.tiles}, nil
+	default:
+		return nil, fmt.Errorf("unknown format `%s`", format)
+	}
+}
+
+// Flush the encoder if it buffers output
+func Flush(enc Encoder) error {
+	if f, ok := enc.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// tabulate returns the header and rows representing the value
+func tabulate(v any) ([]string, [][]string, error) {
+	switch t := v.(type) {
+	case Tabular:
+		return t.Header(), t.Rows(), nil
+	case []string:
+		return nil, [][]string{t}, nil
+	case map[string]string:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		rows := make([][]string, len(keys))
+		for i, key := range keys {
+			rows[i] = []string{key, t[key]}
+		}
+		return []string{"name", "value"}, rows, nil
+	}
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Slice {
+		var header []string
+		var rows [][]string
+		for i := range val.Len() {
+			h, r, err := tabulate(val.Index(i).Interface())
+			if err != nil {
+				return nil, nil, err
+			}
+			header = h
+			rows = append(rows, r...)
+		}
+		return header, rows, nil
+	}
+	// values without a tabular representation are a single json column
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{"value"}, [][]string{{string(data)}}, nil
+}
+
+// ndjsonEncoder writes each element of a slice as a line of json
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(v any) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Slice {
+		return e.enc.Encode(v)
+	}
+	for i := range val.Len() {
+		if err := e.enc.Encode(val.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlEncoder writes yaml using the json field names and order
+type yamlEncoder struct {
+	enc *yaml.Encoder
+}
+
+func (e *yamlEncoder) Encode(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	// json is flow style yaml so reset the style for readability
+	var block func(*yaml.Node)
+	block = func(n *yaml.Node) {
+		n.Style = 0
+		for _, c := range n.Content {
+			block(c)
+		}
+	}
+	block(&node)
+	return e.enc.Encode(&node)
+}
+
+func (e *yamlEncoder) Flush() error {
+	return e.enc.Close()
+}
+
+// csvEncoder writes the header once followed by the rows of each value
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) Encode(v any) error {
+	header, rows, err := tabulate(v)
+	if err != nil {
+		return err
+	}
+	if !e.header && header != nil {
+		e.header = true
+		if err = e.w.Write(header); err != nil {
+			return err
+		}
+	}
+	if err = e.w.WriteAll(rows); err != nil {
+		return err
+	}
+	return e.w.Error()
+}
+
+// tableEncoder writes aligned columns, buffering until flushed
+type tableEncoder struct {
+	w      *tabwriter.Writer
+	header bool
+}
+
+func (e *tableEncoder) Encode(v any) error {
+	header, rows, err := tabulate(v)
+	if err != nil {
+		return err
+	}
+	if !e.header && header != nil {
+		e.header = true
+		if _, err = fmt.Fprintln(e.w, strings.Join(header, "\t")); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if _, err = fmt.Fprintln(e.w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *tableEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+// textEncoder writes the rows of each value as tab separated lines without a header
+// or, if configured, renders the value as tiles
+type textEncoder struct {
+	w     io.Writer
+	tiles *Tiles
+}
+
+func (e *textEncoder) Encode(v any) error {
+	if r, ok := v.(Renderer); ok && e.tiles != nil {
+		return r.Render(e.w, e.tiles)
+	}
+	_, rows, err := tabulate(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, row := range rows {
+		buf.WriteString(strings.Join(row, "\t"))
+		buf.WriteByte('\n')
+	}
+	_, err = e.w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,169 @@
+package qordle_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	board := &qordle.Scoreboard{
+		Target:     "table",
+		Strategy:   "position",
+		Dictionary: 10,
+		Rounds:     []*qordle.Round{{Scores: []string{"so.arE", "TABLE"}, Success: true}},
+		Elapsed:    7,
+	}
+	for _, tt := range []struct {
+		name, format, result, err string
+		values                    []any
+	}{
+		{
+			name:   "json",
+			format: "json",
+			values: []any{qordle.Dictionary{"table", "cable"}},
+			result: "[\"table\",\"cable\"]\n",
+		},
+		{
+			name:   "ndjson",
+			format: "ndjson",
+			values: []any{qordle.Dictionary{"table", "cable"}, map[string]string{"a": "b"}},
+			result: "\"table\"\n\"cable\"\n{\"a\":\"b\"}\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			values: []any{map[string]any{"ok": true, "words": []string{"null", "table"}}},
+			result: "ok: true\nwords:\n    - \"null\"\n    - table\n",
+		},
+		{
+			name:   "csv header written once",
+			format: "csv",
+			values: []any{board, board},
+			result: "target,strategy,dictionary,rounds,success,scores,elapsed\n" +
+				"table,position,10,1,true,so.arE TABLE,7\n" +
+				"table,position,10,1,true,so.arE TABLE,7\n",
+		},
+		{
+			name:   "csv word per line",
+			format: "csv",
+			values: []any{qordle.Dictionary{"table", "cable"}},
+			result: "word\ntable\ncable\n",
+		},
+		{
+			name:   "table",
+			format: "table",
+			values: []any{map[string]string{"version": "1.0", "build": "abc"}},
+			result: "name     value\nbuild    abc\nversion  1.0\n",
+		},
+		{
+			name:   "text solution per line",
+			format: "text",
+			values: []any{[]string{"pythons", "sheared"}, []string{"dry"}},
+			result: "pythons\tsheared\ndry\n",
+		},
+		{
+			name:   "text json column",
+			format: "text",
+			values: []any{map[string]int{"a": 1}},
+			result: "{\"a\":1}\n",
+		},
+		{
+			name:   "unknown format",
+			format: "xml",
+			err:    "unknown format `xml`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			var buf bytes.Buffer
+			enc, err := qordle.NewEncoder(&buf, tt.format)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			for _, v := range tt.values {
+				a.NoError(enc.Encode(v))
+			}
+			a.NoError(qordle.Flush(enc))
+			a.Equal(tt.result, buf.String())
+		})
+	}
+}
+
+func TestEncoderCommands(t *testing.T) {
+	a := assert.New(t)
+	format := func(name string) cli.BeforeFunc {
+		return func(c *cli.Context) error {
+			enc, err := qordle.NewEncoder(c.App.Writer, name)
+			qordle.Runtime(c).Encoder = enc
+			return err
+		}
+	}
+	lines := func(c *cli.Context) []string {
+		return strings.Split(strings.TrimSpace(c.App.Writer.(*bytes.Buffer).String()), "\n")
+	}
+	for _, tt := range []struct {
+		harness
+		cmd func() *cli.Command
+	}{
+		{
+			cmd: qordle.CommandPlay,
+			harness: harness{
+				name:   "play row per secret",
+				args:   []string{"play", "--concurrent", "2", "--start", "soare", "table", "shine"},
+				before: format("csv"),
+				after: func(c *cli.Context) error {
+					res := lines(c)
+					a.Len(res, 3)
+					a.True(strings.HasPrefix(res[0], "target,"))
+					a.True(strings.HasPrefix(res[1], "table,"))
+					a.True(strings.HasPrefix(res[2], "shine,"))
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandSuggest,
+			harness: harness{
+				name:   "suggest word per line",
+				args:   []string{"suggest", "-s", "alpha", "bR.a.in"},
+				before: format("text"),
+				after: func(c *cli.Context) error {
+					a.Equal([]string{"archi", "ardri", "arias"}, lines(c)[:3])
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandLetterBoxed,
+			harness: harness{
+				name:   "letterboxed solution per line",
+				args:   []string{"letterboxed", "-w", "solutions", "--max", "4", "rul-eya-gdh-opb"},
+				before: format("text"),
+				after: func(c *cli.Context) error {
+					res := lines(c)
+					a.Len(res, 57)
+					for _, line := range res {
+						a.NotContains(line, "[")
+					}
+					return nil
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt.harness, tt.cmd)
+		})
+	}
+}
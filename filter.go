@@ -2,6 +2,9 @@ package qordle
 
 import (
 	"errors"
+	"fmt"
+	"maps"
+	"regexp"
 	"strings"
 	"unicode"
 
@@ -76,6 +79,97 @@ func Length(length int) FilterFunc {
 	}
 }
 
+// Contains matches words containing every letter, as many times as the letter is repeated
+func Contains(letters string) FilterFunc {
+	counts := make(map[rune]int)
+	for _, r := range letters {
+		counts[r]++
+	}
+	return func(word string) bool {
+		for r, n := range counts {
+			if strings.Count(word, string(r)) < n {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Lacks matches words containing none of the letters
+func Lacks(letters string) FilterFunc {
+	return func(word string) bool {
+		return !strings.ContainsAny(word, letters)
+	}
+}
+
+// Unique matches words without any repeated letters
+func Unique() FilterFunc {
+	return func(word string) bool {
+		seen := make(map[rune]bool, len(word))
+		for _, r := range word {
+			if seen[r] {
+				return false
+			}
+			seen[r] = true
+		}
+		return true
+	}
+}
+
+// Vowels matches words with exactly `n` vowels, not counting `y`
+func Vowels(n int) FilterFunc {
+	return func(word string) bool {
+		var count int
+		for _, r := range word {
+			if strings.ContainsRune("aeiou", r) {
+				count++
+			}
+		}
+		return count == n
+	}
+}
+
+// Prefix matches words starting with the prefix
+func Prefix(prefix string) FilterFunc {
+	return func(word string) bool {
+		return strings.HasPrefix(word, prefix)
+	}
+}
+
+// Suffix matches words ending with the suffix
+func Suffix(suffix string) FilterFunc {
+	return func(word string) bool {
+		return strings.HasSuffix(word, suffix)
+	}
+}
+
+// Regex matches words matching the regular expression anywhere in the word
+func Regex(expr string) (FilterFunc, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex `%s`: %w", expr, err)
+	}
+	return re.MatchString, nil
+}
+
+// Wildcard matches whole words with `?` matching any one letter and `*` any number of letters
+func Wildcard(pattern string) (FilterFunc, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '?':
+			expr.WriteString(".")
+		case '*':
+			expr.WriteString(".*")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return Regex(expr.String())
+}
+
 func filter(criteria criteria, required map[rune]int) FilterFunc {
 	return func(word string) bool {
 		if len(word) != len(criteria) {
@@ -173,8 +267,11 @@ func compile(marks parsed) (criteria, map[rune]int) {
 	return crit, required
 }
 
+// maxUnknown is the most unknown marks in a pattern since each triples the marks to consider
+const maxUnknown = 6
+
 func parse(feedback string) (parsed, error) {
-	ix, rs, marks := 0, []rune(feedback), make(parsed)
+	ix, rs, marks, unknowns := 0, []rune(feedback), make(parsed), 0
 	for i := 0; i < len(rs); i++ {
 		var mark Mark
 		switch {
@@ -187,6 +284,7 @@ func parse(feedback string) (parsed, error) {
 		case unicode.IsUpper(rs[i]):
 			mark = MarkExact
 		default:
+			wildcard := rs[i] == unknown
 			i++
 			switch {
 			case i >= len(rs):
@@ -197,6 +295,17 @@ func parse(feedback string) (parsed, error) {
 					Str("reason", "length").
 					Msg("parse")
 				return nil, ErrInvalidFormat
+			case wildcard && unicode.IsUpper(rs[i]):
+				mark = MarkUnknown
+				if unknowns++; unknowns > maxUnknown {
+					log.Debug().
+						Str("feedback", feedback).
+						Int("i", i).
+						Int("max", maxUnknown).
+						Str("reason", "unknown").
+						Msg("parse")
+					return nil, ErrInvalidFormat
+				}
 			case unicode.IsLower(rs[i]):
 				mark = MarkMisplaced
 			default:
@@ -221,6 +330,38 @@ func parse(feedback string) (parsed, error) {
 	return marks, nil
 }
 
+func (p parsed) clone() parsed {
+	q := make(parsed, len(p))
+	for letter, states := range p {
+		q[letter] = maps.Clone(states)
+	}
+	return q
+}
+
+// expand replaces each unknown mark with every possible mark
+func expand(marks parsed) []parsed {
+	res := []parsed{marks}
+	for letter, states := range marks {
+		for i, mark := range states {
+			if mark != MarkUnknown {
+				continue
+			}
+			next := make([]parsed, 0, 3*len(res))
+			for _, p := range res {
+				for _, m := range []Mark{MarkMiss, MarkMisplaced, MarkExact} {
+					q := p.clone()
+					q[letter][i] = m
+					next = append(next, q)
+				}
+			}
+			res = next
+		}
+	}
+	return res
+}
+
+// Guess matches words consistent with all the feedback patterns where a tile marked unknown,
+// eg `?A`, may have been any of a miss, misplaced or exact
 func Guess(guesses ...string) (FilterFunc, error) {
 	var fns []FilterFunc
 	for _, guess := range guesses {
@@ -228,8 +369,24 @@ func Guess(guesses ...string) (FilterFunc, error) {
 		if err != nil {
 			return nil, err
 		}
-		fns = append(fns, filter(compile(marks)))
+		var alternatives []FilterFunc
+		for _, m := range expand(marks) {
+			alternatives = append(alternatives, filter(compile(m)))
+		}
+		fns = append(fns, func(word string) bool {
+			for _, fn := range alternatives {
+				if fn(word) {
+					return true
+				}
+			}
+			return false
+		})
 	}
+	return all(fns), nil
+}
+
+// all matches words matched by every filter
+func all(fns []FilterFunc) FilterFunc {
 	return func(word string) bool {
 		for _, fn := range fns {
 			if !fn(word) {
@@ -237,5 +394,56 @@ func Guess(guesses ...string) (FilterFunc, error) {
 			}
 		}
 		return true
-	}, nil
+	}
+}
+
+// unparse returns the guessed word and its marks in order of position
+func unparse(marks parsed) (string, Marks) {
+	var n int
+	for _, states := range marks {
+		n += len(states)
+	}
+	letters, res := make([]rune, n), make(Marks, n)
+	for letter, states := range marks {
+		for i, mark := range states {
+			letters[i], res[i] = letter, mark
+		}
+	}
+	return string(letters), res
+}
+
+// GuessLies matches words for which exactly `lies` tiles of each feedback pattern have a false
+// mark, as in Fibble, not counting the tiles marked unknown
+func GuessLies(lies int, guesses ...string) (FilterFunc, error) {
+	switch {
+	case lies < 0:
+		return nil, errors.New("lies must not be negative")
+	case lies == 0:
+		return Guess(guesses...)
+	}
+	var fns []FilterFunc
+	for _, guess := range guesses {
+		marks, err := parse(guess)
+		if err != nil {
+			return nil, err
+		}
+		word, shown := unparse(marks)
+		fns = append(fns, func(secret string) bool {
+			if len(secret) != len(word) {
+				return false
+			}
+			checks, err := Check(secret, word)
+			if err != nil {
+				return false
+			}
+			var wrong int
+			for i := range shown {
+				if shown[i] != MarkUnknown && shown[i] != checks[0][i] {
+					wrong++
+				}
+			}
+			return wrong == lies
+		})
+	}
+	return all(fns), nil
 }
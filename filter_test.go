@@ -2,7 +2,11 @@ package qordle_test
 
 import (
 	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 
@@ -160,6 +164,66 @@ func TestGuesses(t *testing.T) {
 			guesses: []string{"br#ain", "#l#EgAl"},
 			err:     qordle.ErrInvalidFormat,
 		},
+		{
+			name:    "unknown mark as a miss",
+			word:    "spelt",
+			guesses: []string{"br?Ain"},
+			result:  true,
+		},
+		{
+			name:    "unknown mark as misplaced",
+			word:    "pleat",
+			guesses: []string{"br?Ain"},
+			result:  true,
+		},
+		{
+			name:    "unknown mark as exact",
+			word:    "plant",
+			guesses: []string{"PL?ANT"},
+			result:  true,
+		},
+		{
+			name:    "question mark before a lowercase letter is misplaced",
+			word:    "spelt",
+			guesses: []string{"br?ain"},
+			result:  false,
+		},
+		{
+			name:    "question mark before a lowercase letter is misplaced for a match",
+			word:    "pleat",
+			guesses: []string{"br?ain"},
+			result:  true,
+		},
+		{
+			name:    "unknown mark inconsistent with any mark",
+			word:    "spite",
+			guesses: []string{"cr?Ane"},
+			result:  false,
+		},
+		{
+			name:    "unknown marks with other guesses",
+			word:    "pleat",
+			guesses: []string{"?B?R?A?I?N", "#l#egAl"},
+			result:  true,
+		},
+		{
+			name:    "unknown mark without a letter",
+			word:    "pleat",
+			guesses: []string{"brain?"},
+			err:     qordle.ErrInvalidFormat,
+		},
+		{
+			name:    "too many unknown marks",
+			word:    "pleasant",
+			guesses: []string{"?B?R?A?I?N?E?D?S"},
+			err:     qordle.ErrInvalidFormat,
+		},
+		{
+			name:    "repeated unknown mark",
+			word:    "pleat",
+			guesses: []string{"br??Ain"},
+			err:     qordle.ErrInvalidFormat,
+		},
 		{
 			name:    "error with poor format",
 			word:    "pleat",
@@ -185,7 +249,7 @@ func TestGuesses(t *testing.T) {
 }
 
 func FuzzGuesses(f *testing.F) {
-	for _, x := range []string{"br#ain", "#l#EgAl", "foo", "start", "12345", "rüsch"} {
+	for _, x := range []string{"br#ain", "#l#EgAl", "foo", "start", "12345", "rüsch", "br?Ain"} {
 		f.Add(x)
 	}
 	f.Fuzz(func(_ *testing.T, s string) {
@@ -196,6 +260,163 @@ func FuzzGuesses(f *testing.F) {
 	})
 }
 
+func TestGuessLies(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, word string
+		lies       int
+		guesses    []string
+		result     bool
+		err        string
+	}{
+		{
+			name:    "no lies",
+			word:    "pleat",
+			guesses: []string{"brain"},
+			result:  false,
+		},
+		{
+			name:    "one lie",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"brain"},
+			result:  true,
+		},
+		{
+			name:    "truthful pattern with one lie",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"PLEAT"},
+			result:  false,
+		},
+		{
+			name:    "too few lies",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"Brain"},
+			result:  false,
+		},
+		{
+			name:    "two lies",
+			word:    "pleat",
+			lies:    2,
+			guesses: []string{"Brain"},
+			result:  true,
+		},
+		{
+			name:    "one lie in every pattern",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"brain", "PLEAt"},
+			result:  true,
+		},
+		{
+			name:    "no lie in one pattern",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"brain", "br.ain"},
+			result:  false,
+		},
+		{
+			name:    "unknown marks are never lies",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"?Brain"},
+			result:  true,
+		},
+		{
+			name:    "two tiles off with one lie",
+			word:    "civic",
+			lies:    1,
+			guesses: []string{"Co.nch"},
+			result:  false,
+		},
+		{
+			name:    "different length",
+			word:    "pleats",
+			lies:    1,
+			guesses: []string{"brain"},
+			result:  false,
+		},
+		{
+			name:    "negative lies",
+			word:    "pleat",
+			lies:    -1,
+			guesses: []string{"brain"},
+			err:     "lies must not be negative",
+		},
+		{
+			name:    "invalid pattern",
+			word:    "pleat",
+			lies:    1,
+			guesses: []string{"brain."},
+			err:     qordle.ErrInvalidFormat.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			ff, err := qordle.GuessLies(tt.lies, tt.guesses...)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, ff(tt.word))
+		})
+	}
+}
+
+func TestGuessLiesCheck(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+	// pattern formats the marks of the guess as a feedback pattern
+	pattern := func(guess string, marks qordle.Marks) string {
+		var buf strings.Builder
+		for i, r := range guess {
+			switch marks[i] {
+			case qordle.MarkExact:
+				buf.WriteRune(unicode.ToUpper(r))
+			case qordle.MarkMisplaced:
+				buf.WriteRune('.')
+				buf.WriteRune(r)
+			default:
+				buf.WriteRune(r)
+			}
+		}
+		return buf.String()
+	}
+	rng := rand.New(rand.NewPCG(1, 1)) //nolint:gosec // G404: reproducibility matters, not security
+	for range 25 {
+		secret, guess := dt[rng.IntN(len(dt))], dt[rng.IntN(len(dt))]
+		lies := 1 + rng.IntN(2)
+		checks, err := qordle.Check(secret, guess)
+		a.NoError(err)
+		shown := slices.Clone(checks[0])
+		for _, i := range rng.Perm(len(shown))[:lies] {
+			shown[i] = (shown[i] + qordle.Mark(1+rng.IntN(2))) % 3
+		}
+		fn, err := qordle.GuessLies(lies, pattern(guess, shown))
+		a.NoError(err)
+		a.True(fn(secret))
+		// the ground truth is a word whose marks differ from those shown in exactly `lies` tiles
+		for _, word := range dt {
+			checks, err := qordle.Check(word, guess)
+			a.NoError(err)
+			var wrong int
+			for i := range shown {
+				if shown[i] != checks[0][i] {
+					wrong++
+				}
+			}
+			a.Equal(wrong == lies, fn(word), "secret %s guess %s word %s", secret, guess, word)
+		}
+	}
+}
+
 func TestFilter(t *testing.T) {
 	t.Parallel()
 	for _, tt := range []struct {
@@ -236,3 +457,117 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryFilters(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"crane", "chase", "geese", "nymph", "spree", "crate", "react"}
+	for _, tt := range []struct {
+		name   string
+		fn     qordle.FilterFunc
+		result qordle.Dictionary
+	}{
+		{
+			name:   "contains",
+			fn:     qordle.Contains("rc"),
+			result: qordle.Dictionary{"crane", "crate", "react"},
+		},
+		{
+			name:   "contains repeated letters",
+			fn:     qordle.Contains("ee"),
+			result: qordle.Dictionary{"geese", "spree"},
+		},
+		{
+			name:   "lacks",
+			fn:     qordle.Lacks("ae"),
+			result: qordle.Dictionary{"nymph"},
+		},
+		{
+			name:   "unique",
+			fn:     qordle.Unique(),
+			result: qordle.Dictionary{"crane", "chase", "nymph", "crate", "react"},
+		},
+		{
+			name:   "vowels",
+			fn:     qordle.Vowels(0),
+			result: qordle.Dictionary{"nymph"},
+		},
+		{
+			name:   "prefix",
+			fn:     qordle.Prefix("cr"),
+			result: qordle.Dictionary{"crane", "crate"},
+		},
+		{
+			name:   "suffix",
+			fn:     qordle.Suffix("se"),
+			result: qordle.Dictionary{"chase", "geese"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			a.Equal(tt.result, qordle.Filter(words, tt.fn))
+		})
+	}
+}
+
+func TestPatternFilters(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"crane", "crate", "chase", "react", "cranes"}
+	for _, tt := range []struct {
+		name, pattern, err string
+		fn                 func(string) (qordle.FilterFunc, error)
+		result             qordle.Dictionary
+	}{
+		{
+			name:    "regex",
+			pattern: "ra",
+			fn:      qordle.Regex,
+			result:  qordle.Dictionary{"crane", "crate", "cranes"},
+		},
+		{
+			name:    "anchored regex",
+			pattern: "^c.a.e$",
+			fn:      qordle.Regex,
+			result:  qordle.Dictionary{"crane", "crate", "chase"},
+		},
+		{
+			name:    "invalid regex",
+			pattern: "(",
+			fn:      qordle.Regex,
+			err:     "invalid regex `(`: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:    "wildcard",
+			pattern: "c?a?e",
+			fn:      qordle.Wildcard,
+			result:  qordle.Dictionary{"crane", "crate", "chase"},
+		},
+		{
+			name:    "wildcard any letters",
+			pattern: "cr*",
+			fn:      qordle.Wildcard,
+			result:  qordle.Dictionary{"crane", "crate", "cranes"},
+		},
+		{
+			name:    "wildcard literals",
+			pattern: "c.a.e",
+			fn:      qordle.Wildcard,
+			result:  qordle.Dictionary{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			fn, err := tt.fn(tt.pattern)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				a.Nil(fn)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, qordle.Filter(words, fn))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package qordle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ReadHistory reads the previous answers recorded in the history file, none if it does not exist
+func ReadHistory(filename string) (Dictionary, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	fp, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer fp.Close()
+	return ReadWordlist(fp)
+}
+
+// AppendHistory records the answers not already in the history file, returning those added
+func AppendHistory(filename string, answers ...string) (Dictionary, error) {
+	history, err := ReadHistory(filename)
+	if err != nil {
+		return nil, err
+	}
+	var added Dictionary
+	seen := history.set()
+	for _, answer := range answers {
+		word := strings.ToLower(strings.TrimSpace(answer))
+		if word != "" && !seen[word] {
+			seen[word] = true
+			added = append(added, word)
+		}
+	}
+	if len(added) == 0 {
+		return added, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return nil, err
+	}
+	fp, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	for _, word := range added {
+		if _, err = fmt.Fprintln(fp, word); err != nil {
+			return nil, err
+		}
+	}
+	return added, nil
+}
+
+func CommandHistory() *cli.Command {
+	flags := func() []cli.Flag {
+		return []cli.Flag{
+			&cli.PathFlag{
+				Name:     "history",
+				Usage:    "the history file of previous answers",
+				EnvVars:  []string{"QORDLE_HISTORY"},
+				Required: true,
+			},
+		}
+	}
+	return &cli.Command{
+		Name:     "history",
+		Category: categoryWordle,
+		Usage:    "Manage the history of previous answers excluded by `suggest` and `play`",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List the previous answers",
+				Flags: flags(),
+				Action: func(c *cli.Context) error {
+					history, err := ReadHistory(c.Path("history"))
+					if err != nil {
+						return err
+					}
+					if history == nil {
+						history = Dictionary{}
+					}
+					return Runtime(c).Encoder.Encode(history)
+				},
+			},
+			{
+				Name:      "add",
+				Usage:     "Record the answers in the history",
+				ArgsUsage: "<answer> [, <answer>]",
+				Flags:     flags(),
+				Action: func(c *cli.Context) error {
+					added, err := AppendHistory(c.Path("history"), c.Args().Slice()...)
+					if err != nil {
+						return err
+					}
+					if added == nil {
+						added = Dictionary{}
+					}
+					return Runtime(c).Encoder.Encode(added)
+				},
+			},
+		},
+	}
+}
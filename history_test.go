@@ -0,0 +1,132 @@
+package qordle_test
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	filename := filepath.Join(t.TempDir(), "qordle", "history.txt")
+
+	history, err := qordle.ReadHistory(filename)
+	a.NoError(err)
+	a.Empty(history)
+
+	added, err := qordle.AppendHistory(filename, "Table", "cable", "table", " ")
+	a.NoError(err)
+	a.Equal(qordle.Dictionary{"table", "cable"}, added)
+
+	added, err = qordle.AppendHistory(filename, "cable", "fable")
+	a.NoError(err)
+	a.Equal(qordle.Dictionary{"fable"}, added)
+
+	added, err = qordle.AppendHistory(filename, "fable")
+	a.NoError(err)
+	a.Empty(added)
+
+	history, err = qordle.ReadHistory(filename)
+	a.NoError(err)
+	a.Equal(qordle.Dictionary{"table", "cable", "fable"}, history)
+
+	history, err = qordle.ReadHistory("")
+	a.NoError(err)
+	a.Nil(history)
+
+	_, err = qordle.ReadHistory(filepath.Dir(filename))
+	a.Error(err)
+}
+
+func TestHistoryCommand(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "history.txt")
+	decode := func(c *cli.Context) []string {
+		var res []string
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return res
+	}
+	for _, tt := range []harness{
+		{
+			name: "empty history",
+			args: []string{"history", "list", "--history", filename},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "add answers",
+			args: []string{"history", "add", "--history", filename, "table", "cable"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"table", "cable"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "add recorded answers",
+			args: []string{"history", "add", "--history", filename, "table"},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "list answers",
+			args: []string{"history", "list", "--history", filename},
+			after: func(c *cli.Context) error {
+				a.Equal([]string{"table", "cable"}, decode(c))
+				return nil
+			},
+		},
+		{
+			name: "missing history",
+			args: []string{"history", "list"},
+			err:  "Required flag \"history\" not set",
+		},
+		{
+			name: "unwritable history",
+			args: []string{"history", "add", "--history", dir, "table"},
+			err:  "is a directory",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandHistory)
+		})
+	}
+}
+
+func TestPlayHistory(t *testing.T) {
+	a := assert.New(t)
+	filename := filepath.Join(t.TempDir(), "history.txt")
+	dictionary, err := qordle.Read("possible")
+	a.NoError(err)
+	history := qordle.Sample(dictionary, 2, 7)
+	_, err = qordle.AppendHistory(filename, history...)
+	a.NoError(err)
+
+	tt := &harness{
+		name: "previous answers are not played",
+		args: []string{"play", "--start", "soare", "--sample", "3", "--seed", "7", "--history", filename},
+		after: func(c *cli.Context) error {
+			dec := json.NewDecoder(c.App.Writer.(io.Reader))
+			for range 3 {
+				var res qordle.Scoreboard
+				a.NoError(dec.Decode(&res))
+				a.NotContains(history, res.Target)
+				a.Equal(filename, res.Selection.History)
+			}
+			return nil
+		},
+	}
+	run(t, tt, qordle.CommandPlay)
+}
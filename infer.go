@@ -0,0 +1,299 @@
+package qordle
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
+
+// key encodes the marks as a base three number
+func (m Marks) key() int {
+	var key int
+	for _, mark := range m {
+		key = key*3 + int(mark)
+	}
+	return key
+}
+
+// Achievable is the number of guesses producing each pattern of marks against a secret
+type Achievable map[int]int
+
+// NewAchievable counts the guesses producing each pattern of marks against the secret
+func NewAchievable(secret string, guesses Dictionary) (Achievable, error) {
+	checks, err := Check(secret, guesses...)
+	if err != nil {
+		return nil, err
+	}
+	achievable := make(Achievable)
+	for i := range checks {
+		achievable[checks[i].key()]++
+	}
+	return achievable, nil
+}
+
+// Contains returns true if some guess produces the marks
+func (a Achievable) Contains(marks Marks) bool {
+	return a[marks.key()] > 0
+}
+
+// ReadGrids reads the grids of shared games, separated by blank lines or any other line which
+// is not a row of tiles, such as the `Wordle 1,234 3/6` header
+func ReadGrids(r io.Reader) ([]Grid, error) {
+	if r == nil {
+		return nil, errors.New("invalid reader")
+	}
+	var grids []Grid
+	var grid Grid
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		row, err := ParseGrid(scanner.Text())
+		if err != nil || len(row) == 0 {
+			if len(grid) > 0 {
+				grids = append(grids, grid)
+			}
+			grid = nil
+			continue
+		}
+		grid = append(grid, row...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(grid) > 0 {
+		grids = append(grids, grid)
+	}
+	return grids, nil
+}
+
+// Inferred is a secret for which every row of every grid is producible by some guess
+type Inferred struct {
+	Rank   int    `json:"rank"`
+	Secret string `json:"secret"`
+	// Likelihood is the log probability of the grids if each guess was equally likely
+	Likelihood float64 `json:"likelihood"`
+	// Probability is the chance of the secret among the candidates
+	Probability float64 `json:"probability"`
+}
+
+// Inference is the secrets consistent with the grids shared for the same puzzle
+type Inference struct {
+	Grids      int         `json:"grids"`
+	Patterns   int         `json:"patterns"`
+	Secrets    int         `json:"secrets"`
+	Candidates []*Inferred `json:"candidates"`
+}
+
+func (n *Inference) Header() []string {
+	return []string{"rank", "secret", "probability", "likelihood"}
+}
+
+func (n *Inference) Rows() [][]string {
+	rows := make([][]string, len(n.Candidates))
+	for i, c := range n.Candidates {
+		rows[i] = []string{
+			strconv.Itoa(c.Rank),
+			c.Secret,
+			strconv.FormatFloat(c.Probability, 'f', 4, 64),
+			strconv.FormatFloat(c.Likelihood, 'f', 4, 64),
+		}
+	}
+	return rows
+}
+
+// Infer finds the secrets for which every row of every grid is producible by some guess, ranked
+// by the likelihood of the grids if each guess producing a row was equally likely to be played
+func Infer(ctx context.Context, secrets, guesses Dictionary, concurrent int, grids ...Grid) (*Inference, error) {
+	if len(grids) == 0 {
+		return nil, errors.New("missing grids")
+	}
+	var n int
+	var rows []Marks
+	for i := range grids {
+		if len(grids[i]) == 0 {
+			continue
+		}
+		n++
+		for j := range grids[i] {
+			if len(rows) > 0 && len(grids[i][j]) != len(rows[0]) {
+				return nil, fmt.Errorf("grid %d: %w", i+1, ErrInvalidLength)
+			}
+			rows = append(rows, grids[i][j])
+		}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("missing rows")
+	}
+	length := len(rows[0])
+	secrets = Filter(secrets, Length(length), IsLower())
+	guesses = Filter(guesses, Length(length), IsLower())
+	if len(guesses) == 0 {
+		return nil, errors.New("empty dictionary")
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range secrets {
+			select {
+			case <-ctx.Done():
+				return
+			case indices <- i:
+			}
+		}
+	}()
+
+	// the achievable patterns of each secret are computed concurrently
+	var wg sync.WaitGroup
+	achievable := make([]Achievable, len(secrets))
+	for range workers(concurrent, len(secrets)) {
+		wg.Go(func() {
+			for i := range indices {
+				a, err := NewAchievable(secrets[i], guesses)
+				if err != nil {
+					cancel(err)
+					return
+				}
+				achievable[i] = a
+			}
+		})
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	inference := &Inference{Grids: n, Patterns: len(rows), Secrets: len(secrets), Candidates: []*Inferred{}}
+	for i, secret := range secrets {
+		var likelihood float64
+		for _, row := range rows {
+			count := achievable[i][row.key()]
+			if count == 0 {
+				likelihood = math.Inf(-1)
+				break
+			}
+			likelihood += math.Log(float64(count) / float64(len(guesses)))
+		}
+		if !math.IsInf(likelihood, -1) {
+			inference.Candidates = append(inference.Candidates, &Inferred{Secret: secret, Likelihood: likelihood})
+		}
+	}
+	sort.SliceStable(inference.Candidates, func(i, j int) bool {
+		x, y := inference.Candidates[i], inference.Candidates[j]
+		if x.Likelihood != y.Likelihood {
+			return x.Likelihood > y.Likelihood
+		}
+		return x.Secret < y.Secret
+	})
+	// normalize relative to the most likely secret to avoid underflow
+	var total float64
+	for _, c := range inference.Candidates {
+		c.Probability = math.Exp(c.Likelihood - inference.Candidates[0].Likelihood)
+		total += c.Probability
+	}
+	for i, c := range inference.Candidates {
+		c.Rank, c.Probability = i+1, c.Probability/total
+	}
+	return inference, nil
+}
+
+// shares reads the grids from each file or else from stdin
+func shares(c *cli.Context) ([]Grid, error) {
+	if c.NArg() == 0 {
+		return ReadGrids(c.App.Reader)
+	}
+	var res []Grid
+	for _, name := range c.Args().Slice() {
+		if name == "-" {
+			g, err := ReadGrids(c.App.Reader)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, g...)
+			continue
+		}
+		fp, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		g, err := ReadGrids(fp)
+		fp.Close()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, g...)
+	}
+	return res, nil
+}
+
+func CommandInfer() *cli.Command {
+	return &cli.Command{
+		Name:      "infer",
+		Category:  categoryWordle,
+		Usage:     "Infer the secret from the grids shared by many players for the same puzzle",
+		ArgsUsage: "[<file>...]",
+		Description: "The grids are read from each file, `-` for stdin, or else from stdin and are " +
+			"separated by blank lines or any other line which is not a row of tiles, such as the " +
+			"`Wordle 1,234 3/6` header. A secret remains a candidate if every row of every grid is " +
+			"produced by some guess and the candidates are ranked by the likelihood of the grids " +
+			"if each guess producing a row was equally likely to be played. The candidate secrets " +
+			"are the solutions unless a word list is specified.",
+		Flags: slices.Concat(
+			[]cli.Flag{
+				&cli.StringSliceFlag{
+					Name:  "guesses",
+					Usage: "the word lists, embedded or files, of the allowed guesses",
+					Value: cli.NewStringSlice("possible", "solutions"),
+				},
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "maximum number of secrets, all if zero",
+				},
+				&cli.IntFlag{
+					Name:  "concurrent",
+					Usage: "number of secrets for which to compute the achievable patterns concurrently",
+				},
+			},
+			wordlistFlags(),
+		),
+		Action: func(c *cli.Context) error {
+			grids, err := shares(c)
+			if err != nil {
+				return err
+			}
+			secrets, err := wordlists(c, "solutions")
+			if err != nil {
+				return err
+			}
+			var guesses Dictionary
+			for _, name := range c.StringSlice("guesses") {
+				words, err := resolve(c, name)
+				if err != nil {
+					return err
+				}
+				guesses = guesses.union(words)
+			}
+			inference, err := Infer(c.Context, secrets, guesses, c.Int("concurrent"), grids...)
+			if err != nil {
+				return err
+			}
+			inference.Candidates, err = Paginate(inference.Candidates, 0, c.Int("limit"))
+			if err != nil {
+				return err
+			}
+			return Runtime(c).Encoder.Encode(inference)
+		},
+	}
+}
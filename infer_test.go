@@ -0,0 +1,213 @@
+package qordle_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+const shares = `Wordle 1,234 3/6
+
+⬛⬛🟨⬛🟨
+🟨🟩⬛⬛⬛
+🟩🟩🟩🟩🟩
+
+Wordle 1,234 4/6*
+
+🟨⬛⬛⬛🟨
+⬛🟩⬛🟩🟩
+⬛🟩🟩🟩🟩
+🟩🟩🟩🟩🟩
+
+Wordle 1,234 2/6
+⬛🟩🟩🟩🟩
+🟩🟩🟩🟩🟩
+`
+
+func TestReadGrids(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	grids, err := qordle.ReadGrids(strings.NewReader(shares))
+	a.NoError(err)
+	a.Len(grids, 3)
+	a.Len(grids[0], 3)
+	a.Len(grids[1], 4)
+	a.Len(grids[2], 2)
+	a.Equal(qordle.Marks{
+		qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkMisplaced,
+	}, grids[1][0])
+
+	grids, err = qordle.ReadGrids(strings.NewReader("\n\n"))
+	a.NoError(err)
+	a.Empty(grids)
+
+	_, err = qordle.ReadGrids(nil)
+	a.EqualError(err, "invalid reader")
+}
+
+func TestAchievable(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	achievable, err := qordle.NewAchievable("table", qordle.Dictionary{"cable", "fable", "brain", "table"})
+	a.NoError(err)
+	grid, err := qordle.ParseGrid("BGGGG", "YBYBB", "GGGGG", "YYYYY")
+	a.NoError(err)
+	a.True(achievable.Contains(grid[0]))
+	a.True(achievable.Contains(grid[1]))
+	a.True(achievable.Contains(grid[2]))
+	a.False(achievable.Contains(grid[3]))
+
+	_, err = qordle.NewAchievable("table", qordle.Dictionary{"tables"})
+	a.ErrorIs(err, qordle.ErrInvalidLength)
+}
+
+func TestInfer(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+	grids, err := qordle.ReadGrids(strings.NewReader(shares))
+	a.NoError(err)
+
+	inference, err := qordle.Infer(context.Background(), dt, dt, 2, grids...)
+	a.NoError(err)
+	a.Equal(3, inference.Grids)
+	a.Equal(9, inference.Patterns)
+	a.NotEmpty(inference.Candidates)
+	var total float64
+	for i, c := range inference.Candidates {
+		a.Equal(i+1, c.Rank)
+		if i > 0 {
+			a.GreaterOrEqual(inference.Candidates[i-1].Likelihood, c.Likelihood)
+		}
+		// every row of every grid is producible against the secret
+		achievable, err := qordle.NewAchievable(c.Secret, dt)
+		a.NoError(err)
+		for _, grid := range grids {
+			for _, row := range grid {
+				a.True(achievable.Contains(row))
+			}
+		}
+		total += c.Probability
+	}
+	a.InDelta(1.0, total, 0.0001)
+	a.Equal([]string{"eater", "hater", "water"}, []string{
+		inference.Candidates[0].Secret, inference.Candidates[1].Secret, inference.Candidates[2].Secret})
+
+	// adding a grid only removes candidates
+	more, err := qordle.ParseGrid("🟩🟩⬛⬛⬛", "🟩🟩🟩🟩🟩")
+	a.NoError(err)
+	narrowed, err := qordle.Infer(context.Background(), dt, dt, 2, append(grids, more)...)
+	a.NoError(err)
+	a.Less(len(narrowed.Candidates), len(inference.Candidates))
+	candidates := make(map[string]bool, len(inference.Candidates))
+	for _, c := range inference.Candidates {
+		candidates[c.Secret] = true
+	}
+	for _, c := range narrowed.Candidates {
+		a.True(candidates[c.Secret])
+	}
+
+	_, err = qordle.Infer(context.Background(), dt, dt, 2)
+	a.EqualError(err, "missing grids")
+	_, err = qordle.Infer(context.Background(), dt, dt, 2, qordle.Grid{}, qordle.Grid{})
+	a.EqualError(err, "missing rows")
+	skipped, err := qordle.Infer(context.Background(), dt, dt, 2, append([]qordle.Grid{{}}, grids...)...)
+	a.NoError(err)
+	a.Equal(len(grids), skipped.Grids)
+	a.Equal(inference.Candidates, skipped.Candidates)
+	short, err := qordle.ParseGrid("GGGG")
+	a.NoError(err)
+	_, err = qordle.Infer(context.Background(), dt, dt, 2, append(grids, short)...)
+	a.ErrorIs(err, qordle.ErrInvalidLength)
+	_, err = qordle.Infer(context.Background(), dt, qordle.Dictionary{"tables"}, 2, grids...)
+	a.EqualError(err, "empty dictionary")
+}
+
+func TestInferCommand(t *testing.T) {
+	a := assert.New(t)
+	guesses := filepath.Join(t.TempDir(), "guesses.txt")
+	a.NoError(os.WriteFile(guesses, []byte("WATER\nhater\neater\nTEARS\n"), 0o600))
+	decode := func(c *cli.Context) *qordle.Inference {
+		var res qordle.Inference
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return &res
+	}
+	for _, tt := range []harness{
+		{
+			name: "stdin",
+			args: []string{"infer", "--guesses", "solutions", "--limit", "3"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader(shares)
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal(3, res.Grids)
+				a.Len(res.Candidates, 3)
+				a.Equal("eater", res.Candidates[0].Secret)
+				return nil
+			},
+		},
+		{
+			name: "guesses from a file",
+			args: []string{"infer", "--guesses", guesses, "-w", "solutions"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader("GGGGG\n")
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				// only a secret among the guesses can be solved
+				res := decode(c)
+				var secrets []string
+				for _, candidate := range res.Candidates {
+					secrets = append(secrets, candidate.Secret)
+				}
+				a.Equal([]string{"eater", "hater", "water"}, secrets)
+				return nil
+			},
+		},
+		{
+			name: "missing grids",
+			args: []string{"infer"},
+			err:  "missing grids",
+		},
+		{
+			name: "missing file",
+			args: []string{"infer", "/this/file/does/not/exist"},
+			err:  "open /this/file/does/not/exist: no such file or directory",
+		},
+		{
+			name: "invalid guesses",
+			args: []string{"infer", "--guesses", "foobar"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader(shares)
+				return nil
+			},
+			err: "invalid wordlist `foobar`",
+		},
+		{
+			name: "negative limit",
+			args: []string{"infer", "--guesses", "solutions", "--limit", "-1"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader(shares)
+				return nil
+			},
+			err: "limit must not be negative",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandInfer)
+		})
+	}
+}
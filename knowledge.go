@@ -0,0 +1,114 @@
+package qordle
+
+import (
+	"sort"
+
+	set "github.com/deckarep/golang-set/v2"
+)
+
+// Knowledge is the game state accumulated from the feedback patterns so far
+type Knowledge struct {
+	patterns []string
+	exact    map[int]rune
+	present  set.Set[rune]
+	absent   set.Set[rune]
+}
+
+// NewKnowledge compiles the feedback patterns into the letters known to be
+// exact, present and absent
+func NewKnowledge(patterns ...string) (*Knowledge, error) {
+	k := &Knowledge{
+		patterns: patterns,
+		exact:    make(map[int]rune),
+		present:  set.NewThreadUnsafeSet[rune](),
+		absent:   set.NewThreadUnsafeSet[rune](),
+	}
+	misses := set.NewThreadUnsafeSet[rune]()
+	for _, pattern := range patterns {
+		marks, err := parse(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for letter, states := range marks {
+			for i, mark := range states {
+				switch mark {
+				case MarkExact:
+					k.exact[i] = letter
+					k.present.Add(letter)
+				case MarkMisplaced:
+					k.present.Add(letter)
+				case MarkMiss:
+					misses.Add(letter)
+				}
+			}
+		}
+	}
+	// a miss only means absent if the letter was never found in any pattern
+	k.absent = misses.Difference(k.present)
+	return k, nil
+}
+
+// Patterns returns the feedback patterns used to build the knowledge
+func (k *Knowledge) Patterns() []string {
+	if k == nil {
+		return nil
+	}
+	return k.patterns
+}
+
+// Exact returns the letter known to be at the index
+func (k *Knowledge) Exact(index int) (rune, bool) {
+	if k == nil {
+		return 0, false
+	}
+	r, ok := k.exact[index]
+	return r, ok
+}
+
+// Resolved returns true if the letter at the index is known
+func (k *Knowledge) Resolved(index int) bool {
+	_, ok := k.Exact(index)
+	return ok
+}
+
+// Present returns true if the letter is known to be in the secret
+func (k *Knowledge) Present(r rune) bool {
+	return k != nil && k.present.Contains(r)
+}
+
+// Absent returns true if the letter is known not to be in the secret
+func (k *Knowledge) Absent(r rune) bool {
+	return k != nil && k.absent.Contains(r)
+}
+
+// Keyboard is the state of the letters known from the feedback patterns
+type Keyboard struct {
+	// Green are the letters known to be in the secret at the position
+	Green map[int]string `json:"green"`
+	// Yellow are the letters known to be in the secret at an unknown position
+	Yellow []string `json:"yellow"`
+	// Absent are the letters known not to be in the secret
+	Absent []string `json:"absent"`
+}
+
+// Keyboard returns the state of the letters known from the feedback patterns
+func (k *Knowledge) Keyboard() *Keyboard {
+	kb := &Keyboard{Green: make(map[int]string), Yellow: []string{}, Absent: []string{}}
+	if k == nil {
+		return kb
+	}
+	placed := set.NewThreadUnsafeSet[rune]()
+	for i, r := range k.exact {
+		kb.Green[i] = string(r)
+		placed.Add(r)
+	}
+	for _, r := range k.present.Difference(placed).ToSlice() {
+		kb.Yellow = append(kb.Yellow, string(r))
+	}
+	for _, r := range k.absent.ToSlice() {
+		kb.Absent = append(kb.Absent, string(r))
+	}
+	sort.Strings(kb.Yellow)
+	sort.Strings(kb.Absent)
+	return kb
+}
@@ -0,0 +1,119 @@
+package qordle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestKnowledge(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, err       string
+		patterns        []string
+		exact           map[int]rune
+		present, absent []rune
+		unknown         []rune
+	}{
+		{
+			name:     "no patterns",
+			patterns: []string{},
+			exact:    map[int]rune{},
+			unknown:  []rune{'a', 'b'},
+		},
+		{
+			name:     "single pattern",
+			patterns: []string{"cRAnE"},
+			exact:    map[int]rune{1: 'r', 2: 'a', 4: 'e'},
+			present:  []rune{'r', 'a', 'e'},
+			absent:   []rune{'c', 'n'},
+			unknown:  []rune{'s', 't'},
+		},
+		{
+			name:     "miss and misplaced for the same letter",
+			patterns: []string{"s.pel.l", "sPeLL"},
+			exact:    map[int]rune{1: 'p', 3: 'l', 4: 'l'},
+			present:  []rune{'p', 'l'},
+			absent:   []rune{'s', 'e'},
+			unknown:  []rune{'a'},
+		},
+		{
+			name:     "unknown mark",
+			patterns: []string{"cr?Ane"},
+			exact:    map[int]rune{},
+			absent:   []rune{'c', 'r', 'n', 'e'},
+			unknown:  []rune{'a'},
+		},
+		{
+			name:     "invalid pattern",
+			patterns: []string{"fol.l."},
+			err:      qordle.ErrInvalidFormat.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			k, err := qordle.NewKnowledge(tt.patterns...)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				a.Nil(k)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.patterns, k.Patterns())
+			for i := range 5 {
+				r, ok := k.Exact(i)
+				x, found := tt.exact[i]
+				a.Equal(found, ok)
+				a.Equal(x, r)
+				a.Equal(found, k.Resolved(i))
+			}
+			for _, r := range tt.present {
+				a.True(k.Present(r))
+				a.False(k.Absent(r))
+			}
+			for _, r := range tt.absent {
+				a.True(k.Absent(r))
+				a.False(k.Present(r))
+			}
+			for _, r := range tt.unknown {
+				a.False(k.Absent(r))
+				a.False(k.Present(r))
+			}
+		})
+	}
+}
+
+func TestKnowledgeNil(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	var k *qordle.Knowledge
+	a.Nil(k.Patterns())
+	a.False(k.Resolved(0))
+	a.False(k.Present('a'))
+	a.False(k.Absent('a'))
+	a.Equal(&qordle.Keyboard{Green: map[int]string{}, Yellow: []string{}, Absent: []string{}}, k.Keyboard())
+}
+
+func TestKeyboard(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	k, err := qordle.NewKnowledge("r.a.ise", "cHAIN")
+	a.NoError(err)
+	a.Equal(&qordle.Keyboard{
+		Green:  map[int]string{1: "h", 2: "a", 3: "i", 4: "n"},
+		Yellow: []string{},
+		Absent: []string{"c", "e", "r", "s"},
+	}, k.Keyboard())
+
+	k, err = qordle.NewKnowledge("r.a.ise", "pLAnT")
+	a.NoError(err)
+	a.Equal(&qordle.Keyboard{
+		Green:  map[int]string{1: "l", 2: "a", 4: "t"},
+		Yellow: []string{"i"},
+		Absent: []string{"e", "n", "p", "r", "s"},
+	}, k.Keyboard())
+}
@@ -0,0 +1,135 @@
+package qordle
+
+import (
+	"crypto/sha256"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Tables are the letter frequencies used to rank words
+type Tables struct {
+	// Frequencies of each letter
+	Frequencies map[rune]float64 `json:"frequencies"`
+	// Positions are the frequencies of each letter by position
+	Positions map[rune]map[int]float64 `json:"positions"`
+	// Bigrams are the frequencies of each pair of adjacent letters
+	Bigrams map[string]float64 `json:"bigrams"`
+}
+
+// defaultTables are the tables generated from the qordle word list
+var defaultTables = &Tables{
+	Frequencies: frequencies,
+	Positions:   positions,
+	Bigrams:     bigrams,
+}
+
+// DefaultTables returns the tables generated from the qordle word list
+func DefaultTables() *Tables {
+	return defaultTables
+}
+
+// NewTables computes the tables from the words for words of any length and alphabet
+func NewTables(words Dictionary) *Tables {
+	t := &Tables{
+		Frequencies: make(map[rune]float64),
+		Positions:   make(map[rune]map[int]float64),
+		Bigrams:     make(map[string]float64),
+	}
+	var letters, grams float64
+	index := make(map[int]float64)
+	for i := range words {
+		word := []rune(words[i])
+		for j := range word {
+			letters++
+			index[j]++
+			t.Frequencies[word[j]]++
+			pos, ok := t.Positions[word[j]]
+			if !ok {
+				pos = make(map[int]float64)
+				t.Positions[word[j]] = pos
+			}
+			pos[j]++
+		}
+		for _, gram := range pairs(words[i]) {
+			grams++
+			t.Bigrams[gram]++
+		}
+	}
+	for letter := range t.Frequencies {
+		t.Frequencies[letter] /= letters
+		for j := range t.Positions[letter] {
+			t.Positions[letter][j] /= index[j]
+		}
+	}
+	for gram := range t.Bigrams {
+		t.Bigrams[gram] /= grams
+	}
+	return t
+}
+
+// pairs returns the bigrams of the word
+func pairs(word string) []string {
+	w := []rune(word)
+	if len(w) < 2 {
+		return nil
+	}
+	grams := make([]string, len(w)-1)
+	for i := range grams {
+		grams[i] = string(w[i : i+2])
+	}
+	return grams
+}
+
+// maxTables is the number of word lists for which the computed tables are kept
+const maxTables = 8
+
+// tablesCache holds the tables computed for the most recent word lists, oldest first
+var tablesCache = struct {
+	sync.Mutex
+	keys   [][sha256.Size]byte
+	tables map[[sha256.Size]byte]*Tables
+}{tables: make(map[[sha256.Size]byte]*Tables)}
+
+// tablesFor returns the tables for the words, computing them only once for the same words
+func tablesFor(words Dictionary) *Tables {
+	// the key is independent of the order of the words since the union of word lists is unordered
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+	key := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+
+	tablesCache.Lock()
+	defer tablesCache.Unlock()
+	if t, ok := tablesCache.tables[key]; ok {
+		return t
+	}
+	if len(tablesCache.keys) == maxTables {
+		delete(tablesCache.tables, tablesCache.keys[0])
+		tablesCache.keys = tablesCache.keys[1:]
+	}
+	t := NewTables(words)
+	tablesCache.keys = append(tablesCache.keys, key)
+	tablesCache.tables[key] = t
+	return t
+}
+
+// Lettered is implemented by strategies which rank words using letter tables
+type Lettered interface {
+	Strategy
+	Tables(*Tables)
+}
+
+// SetTables sets the letter tables for the strategy if supported by the strategy
+func SetTables(strategy Strategy, tables *Tables) {
+	if s, ok := strategy.(Lettered); ok {
+		s.Tables(tables)
+	}
+}
+
+// orDefault returns the tables if not nil else the default tables
+func (t *Tables) orDefault() *Tables {
+	if t == nil {
+		return defaultTables
+	}
+	return t
+}
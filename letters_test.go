@@ -0,0 +1,61 @@
+package qordle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestNewTables(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	tables := qordle.NewTables(qordle.Dictionary{"abc", "abd", "café", "a"})
+	a.InDelta(4.0/11.0, tables.Frequencies['a'], 0.0001)
+	a.InDelta(1.0/11.0, tables.Frequencies['é'], 0.0001)
+	// positions are computed for every index regardless of the word length
+	a.InDelta(0.75, tables.Positions['a'][0], 0.0001)
+	a.InDelta(1.0/3.0, tables.Positions['a'][1], 0.0001)
+	a.InDelta(1.0, tables.Positions['é'][3], 0.0001)
+	// bigrams are pairs of letters, not bytes
+	a.InDelta(2.0/7.0, tables.Bigrams["ab"], 0.0001)
+	a.InDelta(1.0/7.0, tables.Bigrams["fé"], 0.0001)
+
+	tables = qordle.NewTables(nil)
+	a.Empty(tables.Frequencies)
+	a.Empty(tables.Positions)
+	a.Empty(tables.Bigrams)
+
+	tables = qordle.DefaultTables()
+	a.NotEmpty(tables.Frequencies)
+	a.NotEmpty(tables.Positions)
+	a.NotEmpty(tables.Bigrams)
+}
+
+func TestSetTables(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	words := qordle.Dictionary{"easle", "fause", "false", "haste", "halse"}
+	bigram := new(qordle.Bigram)
+	a.Equal(qordle.Dictionary{"haste", "halse", "easle", "false", "fause"}, bigram.Apply(words))
+
+	// the tables favor the bigrams of `fause`
+	tables := qordle.NewTables(qordle.Dictionary{"fause", "fause", "easle"})
+	strategy := qordle.NewSpeculator(words, qordle.NewChain(bigram))
+	qordle.SetTables(strategy, tables)
+	a.Equal(qordle.Dictionary{"fause", "easle", "false", "halse", "haste"}, bigram.Apply(words))
+	a.Equal(bigram.Apply(words), strategy.Apply(words))
+
+	// the generated tables have no positions beyond the seventh letter
+	long := qordle.Dictionary{"aaaaaaaaaz", "aaaaaaaazz", "aaaaaaaaab"}
+	elimination := new(qordle.Elimination)
+	a.Equal("aaaaaaaaab", elimination.Apply(long)[0])
+	qordle.SetTables(elimination, &qordle.Tables{Positions: map[rune]map[int]float64{'z': {9: 1}}})
+	a.Equal(qordle.Dictionary{"aaaaaaaaaz", "aaaaaaaazz", "aaaaaaaaab"}, elimination.Apply(long))
+
+	// no-op for strategies without letter tables
+	qordle.SetTables(new(qordle.Frequency), tables)
+}
@@ -0,0 +1,205 @@
+package qordle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// ngramStart pads the beginning of a word so the first letters have a context
+	ngramStart = '^'
+	// ngramEnd marks the end of a word so the model learns how words end
+	ngramEnd = '$'
+)
+
+func ngramFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "ngram",
+			Usage: "length of the n-grams used by the ngram strategy",
+			Value: 3,
+		},
+		&cli.BoolFlag{
+			Name:  "positional",
+			Usage: "count the n-grams of the ngram strategy by their position in the word",
+		},
+		&cli.Float64Flag{
+			Name:  "smoothing",
+			Usage: "additive smoothing of the n-gram counts of the ngram strategy",
+			Value: 1,
+		},
+		&cli.StringSliceFlag{
+			Name:  "corpus",
+			Usage: "embedded or external word lists training the ngram strategy, the word list in use if not specified",
+		},
+	}
+}
+
+// NGramModel is a language model estimating how much a word looks like the words of a corpus
+type NGramModel struct {
+	size       int
+	positional bool
+	smoothing  float64
+	corpus     Dictionary
+
+	once     sync.Once
+	grams    map[string]float64
+	contexts map[string]float64
+	alphabet float64
+}
+
+// NGramOption provides a configuration mechanism for an NGramModel
+type NGramOption func(*NGramModel)
+
+// WithNGramSize is the length of the n-grams, 2 for bigrams and 3 for trigrams
+func WithNGramSize(size int) NGramOption {
+	return func(m *NGramModel) {
+		m.size = size
+	}
+}
+
+// WithNGramPositional counts the n-grams separately for each position in the word
+func WithNGramPositional(positional bool) NGramOption {
+	return func(m *NGramModel) {
+		m.positional = positional
+	}
+}
+
+// WithNGramSmoothing is the count added to every n-gram so unseen n-grams are not impossible
+func WithNGramSmoothing(smoothing float64) NGramOption {
+	return func(m *NGramModel) {
+		m.smoothing = smoothing
+	}
+}
+
+// NewNGramModel creates a new model trained on the corpus, by default a trigram model with add-one smoothing
+func NewNGramModel(corpus Dictionary, opts ...NGramOption) (*NGramModel, error) {
+	m := &NGramModel{size: 3, smoothing: 1, corpus: corpus}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.size < 1 {
+		return nil, fmt.Errorf("invalid n-gram size %d", m.size)
+	}
+	if m.smoothing <= 0 {
+		return nil, errors.New("smoothing must be positive")
+	}
+	return m, nil
+}
+
+// each calls the function with the context, letter and position of each n-gram of the word
+func (m *NGramModel) each(word string, f func(context string, letter rune, index int)) {
+	padded := []rune(strings.Repeat(string(ngramStart), m.size-1) + word + string(ngramEnd))
+	for i := m.size - 1; i < len(padded); i++ {
+		f(string(padded[i-m.size+1:i]), padded[i], i-m.size+1)
+	}
+}
+
+// key of the n-gram, including its position if positional
+func (m *NGramModel) key(context string, index int) string {
+	if m.positional {
+		return strconv.Itoa(index) + ":" + context
+	}
+	return context
+}
+
+// train counts the n-grams of the corpus, only once and not until first needed
+func (m *NGramModel) train() {
+	m.once.Do(func() {
+		m.grams = make(map[string]float64)
+		m.contexts = make(map[string]float64)
+		letters := map[rune]struct{}{ngramEnd: {}}
+		for _, word := range m.corpus {
+			m.each(word, func(context string, letter rune, index int) {
+				key := m.key(context, index)
+				m.grams[key+string(letter)]++
+				m.contexts[key]++
+				letters[letter] = struct{}{}
+			})
+		}
+		m.alphabet = float64(len(letters))
+	})
+}
+
+// Likelihood is the log probability of the word, higher values being more word-like
+func (m *NGramModel) Likelihood(word string) float64 {
+	m.train()
+	var likelihood float64
+	m.each(word, func(context string, letter rune, index int) {
+		key := m.key(context, index)
+		p := (m.grams[key+string(letter)] + m.smoothing) / (m.contexts[key] + m.smoothing*m.alphabet)
+		likelihood += math.Log(p)
+	})
+	return likelihood
+}
+
+// Modeled is implemented by strategies which rank words using an n-gram model
+type Modeled interface {
+	Strategy
+	Model(*NGramModel)
+}
+
+// SetModel sets the n-gram model for the strategy if supported by the strategy
+func SetModel(strategy Strategy, model *NGramModel) {
+	if s, ok := strategy.(Modeled); ok {
+		s.Model(model)
+	}
+}
+
+// modeled returns the n-gram model configured by the flags trained on the corpus or,
+// if not specified, the dictionary and nil if there is nothing to train on
+func modeled(c *cli.Context, dictionary Dictionary) (*NGramModel, error) {
+	corpus := dictionary
+	if c.IsSet("corpus") {
+		corpus = nil
+		for _, name := range c.StringSlice("corpus") {
+			res, err := resolve(c, name)
+			if err != nil {
+				return nil, err
+			}
+			corpus = corpus.union(res)
+		}
+	}
+	if len(corpus) == 0 {
+		return nil, nil //nolint:nilnil // the strategy trains on the words being sorted
+	}
+	return NewNGramModel(corpus,
+		WithNGramSize(c.Int("ngram")),
+		WithNGramPositional(c.Bool("positional")),
+		WithNGramSmoothing(c.Float64("smoothing")))
+}
+
+// NGram sorts the dictionary by the likelihood of each word under an n-gram model
+type NGram struct {
+	model *NGramModel
+}
+
+func (s *NGram) String() string {
+	return "ngram"
+}
+
+// Model sets the n-gram model, defaulting to a trigram model trained on the words being sorted
+func (s *NGram) Model(model *NGramModel) {
+	s.model = model
+}
+
+func (s *NGram) Apply(words Dictionary) Dictionary {
+	model := s.model
+	if model == nil {
+		// the default options are always valid
+		model, _ = NewNGramModel(words)
+	}
+	scores := make(map[string]float64, len(words))
+	for _, word := range words {
+		scores[word] = model.Likelihood(word)
+	}
+	return mkdictf(scores, frequencies, func(i, j float64) bool {
+		return i > j
+	})
+}
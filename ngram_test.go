@@ -0,0 +1,140 @@
+package qordle_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestNGramModel(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	corpus := qordle.Dictionary{"crane", "crate", "trace", "grace", "brace"}
+	model, err := qordle.NewNGramModel(corpus)
+	a.NoError(err)
+	a.NotNil(model)
+	a.Greater(model.Likelihood("crace"), model.Likelihood("xyzzy"))
+	a.Greater(model.Likelihood("brace"), model.Likelihood("ecarb"))
+	a.Less(model.Likelihood("brace"), 0.0)
+
+	// bigrams ignore the order beyond the previous letter
+	bigram, err := qordle.NewNGramModel(corpus, qordle.WithNGramSize(2))
+	a.NoError(err)
+	a.InDelta(bigram.Likelihood("grate"), bigram.Likelihood("grace")+
+		bigram.Likelihood("crate")-bigram.Likelihood("crace"), 0.0001)
+
+	// positional n-grams distinguish the same letters in different positions
+	positional, err := qordle.NewNGramModel(corpus, qordle.WithNGramSize(1), qordle.WithNGramPositional(true))
+	a.NoError(err)
+	unigram, err := qordle.NewNGramModel(corpus, qordle.WithNGramSize(1))
+	a.NoError(err)
+	a.InDelta(unigram.Likelihood("crane"), unigram.Likelihood("nacre"), 0.0001)
+	a.Greater(positional.Likelihood("crane"), positional.Likelihood("nacre"))
+
+	// less smoothing favors the words of the corpus more
+	sharp, err := qordle.NewNGramModel(corpus, qordle.WithNGramSmoothing(0.01))
+	a.NoError(err)
+	a.Greater(sharp.Likelihood("crane")-sharp.Likelihood("xyzzy"), model.Likelihood("crane")-model.Likelihood("xyzzy"))
+
+	model, err = qordle.NewNGramModel(corpus, qordle.WithNGramSize(0))
+	a.Error(err)
+	a.Nil(model)
+	model, err = qordle.NewNGramModel(corpus, qordle.WithNGramSmoothing(0))
+	a.Error(err)
+	a.Nil(model)
+}
+
+func TestNGram(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	words := qordle.Dictionary{"xylyl", "crane", "qajaq", "slate"}
+	s := new(qordle.NGram)
+	a.Equal("ngram", s.String())
+	// without a model the words are ranked by a model of themselves
+	a.ElementsMatch(words, s.Apply(words))
+	a.Empty(s.Apply(qordle.Dictionary{}))
+
+	solutions, err := qordle.Read("solutions")
+	a.NoError(err)
+	model, err := qordle.NewNGramModel(solutions)
+	a.NoError(err)
+	strategy := qordle.NewSpeculator(solutions, qordle.NewChain(s))
+	qordle.SetModel(strategy, model)
+	res := s.Apply(words)
+	a.ElementsMatch(qordle.Dictionary{"crane", "slate"}, res[:2])
+	// more words than speculated on
+	words = append(words, "pshaw", "fuzzy")
+	a.Equal(s.Apply(words), strategy.Apply(words))
+
+	// no-op for strategies without a model
+	qordle.SetModel(new(qordle.Frequency), model)
+}
+
+func TestNGramCommand(t *testing.T) {
+	a := assert.New(t)
+	for _, tt := range []harness{
+		{
+			name: "corpus",
+			args: []string{"order", "-s", "ngram", "--corpus", "solutions", "xylyl", "qajaq", "crane"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"crane", "qajaq", "xylyl"}, res)
+				return nil
+			},
+		},
+		{
+			name: "default corpus",
+			args: []string{"order", "-s", "ngram", "eeeee", "house"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"house", "eeeee"}, res)
+				return nil
+			},
+		},
+		{
+			name: "positional bigrams",
+			args: []string{
+				"order", "-s", "ngram", "--ngram", "2", "--positional", "--smoothing", "0.5",
+				"--corpus", "solutions", "nacre", "crane",
+			},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"crane", "nacre"}, res)
+				return nil
+			},
+		},
+		{
+			name: "invalid corpus",
+			args: []string{"order", "-s", "ngram", "--corpus", "nonexistent", "crane"},
+			err:  "invalid wordlist `nonexistent`",
+		},
+		{
+			name: "invalid size",
+			args: []string{"order", "-s", "ngram", "--ngram", "0", "crane"},
+			err:  "invalid n-gram size 0",
+		},
+		{
+			name: "invalid smoothing",
+			args: []string{"order", "-s", "ngram", "--smoothing", "-1", "crane"},
+			err:  "smoothing must be positive",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandOrder)
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package qordle
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	sys "runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// maxOpenerLength is the longest word evaluated, keeping every pattern within a uint16
+const maxOpenerLength = 10
+
+// maxOpenerSize is the largest opening set searched
+const maxOpenerSize = 3
+
+// maxOpenerScratch is the most counts each worker allocates, beyond which the buckets are counted
+// in a map since the number of patterns grows exponentially with the length of the words
+const maxOpenerScratch = 1 << 20
+
+// Opener is an opening set of words with the metrics of how it partitions the secrets
+type Opener struct {
+	Words []string `json:"words"`
+	// Expected is the expected number of candidates remaining after the opening
+	Expected float64 `json:"expected"`
+	// Entropy is the information in bits gained by the opening
+	Entropy float64 `json:"entropy"`
+	// Worst is the largest number of candidates remaining after the opening
+	Worst int `json:"worst"`
+	// Buckets is the number of distinct feedback patterns
+	Buckets int `json:"buckets"`
+	// Pct is the win percentage playing the opening followed by the strategy, nil if not played
+	Pct *float64 `json:"pct,omitempty"`
+	// Mean is the mean number of guesses playing the opening followed by the strategy, nil if not played
+	Mean *float64 `json:"mean,omitempty"`
+}
+
+func (o *Opener) Header() []string {
+	return []string{"words", "expected", "entropy", "worst", "buckets", "pct", "mean"}
+}
+
+func (o *Opener) Rows() [][]string {
+	// the win metrics are left empty unless the opening was played
+	var pct, mean string
+	if o.Pct != nil && o.Mean != nil {
+		pct, mean = strconv.FormatFloat(*o.Pct, 'f', 2, 64), strconv.FormatFloat(*o.Mean, 'f', 2, 64)
+	}
+	return [][]string{{
+		strings.Join(o.Words, " "),
+		strconv.FormatFloat(o.Expected, 'f', 2, 64),
+		strconv.FormatFloat(o.Entropy, 'f', 3, 64),
+		strconv.Itoa(o.Worst),
+		strconv.Itoa(o.Buckets),
+		pct,
+		mean,
+	}}
+}
+
+// key uniquely identifies the set of words regardless of order
+func (o *Opener) key() string {
+	words := slices.Clone(o.Words)
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// metrics order openers from best to worst
+var metrics = map[string]func(a, b *Opener) int{
+	"entropy": func(a, b *Opener) int {
+		return cmp.Compare(b.Entropy, a.Entropy)
+	},
+	"expected": func(a, b *Opener) int {
+		return cmp.Compare(a.Expected, b.Expected)
+	},
+	"worst": func(a, b *Opener) int {
+		return cmp.Compare(a.Worst, b.Worst)
+	},
+	"wins": func(a, b *Opener) int {
+		return cmp.Or(cmp.Compare(*b.Pct, *a.Pct), cmp.Compare(*a.Mean, *b.Mean))
+	},
+}
+
+// ranking orders by the metric breaking ties with entropy and then the words
+func ranking(metric func(a, b *Opener) int) func(a, b *Opener) int {
+	return func(a, b *Opener) int {
+		return cmp.Or(
+			metric(a, b),
+			metrics["entropy"](a, b),
+			strings.Compare(strings.Join(a.Words, " "), strings.Join(b.Words, " ")))
+	}
+}
+
+// feedback returns the marks of the guess for the secret as a base 3 number
+func feedback(secret, guess string) int {
+	var counts [256]int8
+	var exact [maxOpenerLength]bool
+	for i := range len(guess) {
+		if secret[i] == guess[i] {
+			exact[i] = true
+		} else {
+			counts[secret[i]]++
+		}
+	}
+	var pattern int
+	for i := range len(guess) {
+		mark := MarkMiss
+		switch {
+		case exact[i]:
+			mark = MarkExact
+		case counts[guess[i]] > 0:
+			counts[guess[i]]--
+			mark = MarkMisplaced
+		}
+		pattern = pattern*3 + int(mark)
+	}
+	return pattern
+}
+
+// partition of the secrets into classes sharing the same feedback for the words
+type partition struct {
+	words   []string
+	classes []int32
+	n       int
+}
+
+// openers searches for the best opening sets of guesses
+type openers struct {
+	guesses    Dictionary
+	secrets    Dictionary
+	base       int
+	concurrent int
+	// patterns holds the feedback of each guess (row) for each secret (column)
+	patterns [][]uint16
+}
+
+func newOpeners(ctx context.Context, guesses, secrets Dictionary, length, concurrent int) (*openers, error) {
+	if length > maxOpenerLength {
+		return nil, fmt.Errorf("length must not exceed %d", maxOpenerLength)
+	}
+	guesses = Filter(guesses, Length(length), IsLower())
+	secrets = Filter(secrets, Length(length), IsLower())
+	if len(guesses) == 0 || len(secrets) == 0 {
+		return nil, errors.New("empty dictionary")
+	}
+	o := &openers{
+		guesses:    guesses,
+		secrets:    secrets,
+		base:       int(math.Pow(3, float64(length))),
+		concurrent: concurrent,
+		patterns:   make([][]uint16, len(guesses)),
+	}
+	err := o.each(ctx, 0, func(i int, _ []int32) {
+		row := make([]uint16, len(secrets))
+		for j := range secrets {
+			row[j] = uint16(feedback(secrets[j], guesses[i])) //nolint:gosec // the length is bounded
+		}
+		o.patterns[i] = row
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// each calls fn for every guess using a pool of workers, each with its own scratch space
+func (o *openers) each(ctx context.Context, scratch int, fn func(i int, scratch []int32)) error {
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range o.guesses {
+			select {
+			case <-ctx.Done():
+				return
+			case indices <- i:
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for range workers(o.concurrent, len(o.guesses)) {
+		wg.Go(func() {
+			var counts []int32
+			if scratch <= maxOpenerScratch {
+				counts = make([]int32, scratch)
+			}
+			for i := range indices {
+				fn(i, counts)
+			}
+		})
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// score the partition refined by the feedback of the guess, counting the buckets in a map
+// if the scratch space is too small
+func (o *openers) score(p *partition, guess int, scratch []int32) *Opener {
+	row := o.patterns[guess]
+	opener := &Opener{Words: append(slices.Clone(p.words), o.guesses[guess])}
+	if len(scratch) < p.n*o.base {
+		buckets := make(map[int]int32)
+		for i := range o.secrets {
+			buckets[int(p.classes[i])*o.base+int(row[i])]++
+		}
+		// iterate in the order of the secrets so the sums do not depend on the map order
+		for i := range o.secrets {
+			key := int(p.classes[i])*o.base + int(row[i])
+			if c, ok := buckets[key]; ok {
+				delete(buckets, key)
+				opener.bucket(c, len(o.secrets))
+			}
+		}
+		return opener
+	}
+	for i := range o.secrets {
+		scratch[int(p.classes[i])*o.base+int(row[i])]++
+	}
+	for i := range o.secrets {
+		key := int(p.classes[i])*o.base + int(row[i])
+		c := scratch[key]
+		if c == 0 {
+			// the bucket was already counted
+			continue
+		}
+		scratch[key] = 0
+		opener.bucket(c, len(o.secrets))
+	}
+	return opener
+}
+
+// bucket adds the metrics of a bucket of `c` of the `n` secrets
+func (o *Opener) bucket(c int32, n int) {
+	q := float64(c) / float64(n)
+	o.Buckets++
+	o.Expected += float64(c) * q
+	o.Entropy -= q * math.Log2(q)
+	o.Worst = max(o.Worst, int(c))
+}
+
+// refine the partition by the feedback of the guess
+func (o *openers) refine(p *partition, guess int) *partition {
+	row := o.patterns[guess]
+	ids := make(map[int]int32)
+	classes := make([]int32, len(o.secrets))
+	for i := range o.secrets {
+		key := int(p.classes[i])*o.base + int(row[i])
+		id, ok := ids[key]
+		if !ok {
+			id = int32(len(ids)) //nolint:gosec // bounded by the number of secrets
+			ids[key] = id
+		}
+		classes[i] = id
+	}
+	return &partition{words: append(slices.Clone(p.words), o.guesses[guess]), classes: classes, n: len(ids)}
+}
+
+// search for the best opening sets of the size keeping the beam best sets at each level
+func (o *openers) search(ctx context.Context, size, beam int, rank func(a, b *Opener) int) ([]*Opener, error) {
+	index := make(map[string]int, len(o.guesses))
+	for i, guess := range o.guesses {
+		index[guess] = i
+	}
+	frontier := []*partition{{classes: make([]int32, len(o.secrets)), n: 1}}
+	var level []*Opener
+	for depth := 1; depth <= size; depth++ {
+		level = level[:0]
+		seen := make(map[string]bool)
+		for _, p := range frontier {
+			scored := make([]*Opener, len(o.guesses))
+			if err := o.each(ctx, p.n*o.base, func(i int, scratch []int32) {
+				if !slices.Contains(p.words, o.guesses[i]) {
+					scored[i] = o.score(p, i, scratch)
+				}
+			}); err != nil {
+				return nil, err
+			}
+			for _, opener := range scored {
+				if opener == nil || seen[opener.key()] {
+					continue
+				}
+				seen[opener.key()] = true
+				level = append(level, opener)
+			}
+		}
+		slices.SortFunc(level, rank)
+		log.Debug().Int("depth", depth).Int("openers", len(level)).Msg("search")
+		if depth == size {
+			break
+		}
+		frontier = frontier[:0]
+		for _, opener := range level[:min(beam, len(level))] {
+			p := &partition{classes: make([]int32, len(o.secrets)), n: 1}
+			for _, word := range opener.Words {
+				p = o.refine(p, index[word])
+			}
+			frontier = append(frontier, p)
+		}
+	}
+	return level, nil
+}
+
+// wins plays the secrets with each opening followed by the strategy
+func wins(c *cli.Context, dictionary Dictionary, secrets []string, candidates []*Opener) error {
+	strategy, err := build(Runtime(c).Strategies, c.StringSlice("strategy"))
+	if err != nil {
+		return err
+	}
+	SetWorkers(strategy, c.Int("workers"))
+	SetTables(strategy, lettered(c, dictionary))
+	model, err := modeled(c, dictionary)
+	if err != nil {
+		return err
+	}
+	SetModel(strategy, model)
+	if c.Bool("speculate") {
+		strategy = NewSpeculator(dictionary, strategy)
+	}
+	contenders := make([]*contender, len(candidates))
+	for i := range candidates {
+		contenders[i] = &contender{
+			config:   strings.Join(candidates[i].Words, " "),
+			strategy: strategy.String(),
+			game: NewGame(
+				WithStrategy(strategy),
+				WithDictionary(dictionary),
+				WithOpening(candidates[i].Words...)),
+		}
+	}
+
+	writer := io.Discard
+	if c.Bool("progress") {
+		writer = c.App.ErrWriter
+	}
+	bar := pb.New(len(contenders) * len(secrets)).SetWriter(writer).Start()
+	defer bar.Finish()
+	outcomes, err := tournament(c.Context, contenders, secrets, c.Int("concurrent"), bar)
+	if err != nil {
+		return err
+	}
+	for i := range candidates {
+		b := benchmark(contenders[i].config, contenders[i].strategy, outcomes[i])
+		candidates[i].Pct, candidates[i].Mean = &b.Pct, &b.Mean
+	}
+	return nil
+}
+
+func opening(c *cli.Context) error {
+	defer func(t time.Time) {
+		log.Info().Dur("elapsed", time.Since(t)).Msg(c.Command.Name)
+	}(time.Now())
+
+	metric, ok := metrics[c.String("metric")]
+	if !ok {
+		return fmt.Errorf("unknown metric `%s`", c.String("metric"))
+	}
+	size := c.Int("size")
+	if size < 1 || size > maxOpenerSize {
+		return fmt.Errorf("size must be between 1 and %d", maxOpenerSize)
+	}
+	guesses, err := wordlists(c, "possible", "solutions")
+	if err != nil {
+		return err
+	}
+	secrets, err := resolve(c, c.String("secrets"))
+	if err != nil {
+		return err
+	}
+	o, err := newOpeners(c.Context, guesses, secrets, c.Int("length"), c.Int("concurrent"))
+	if err != nil {
+		return err
+	}
+
+	// the metric is too expensive to search so the candidates are found by entropy
+	rank := ranking(metric)
+	played := c.String("metric") == "wins"
+	if played {
+		rank = ranking(metrics["entropy"])
+	}
+	res, err := o.search(c.Context, size, c.Int("beam"), rank)
+	if err != nil {
+		return err
+	}
+	top := c.Int("top")
+	if played {
+		res = res[:min(max(top, c.Int("beam")), len(res))]
+		if err = wins(c, o.guesses, o.secrets, res); err != nil {
+			return err
+		}
+		slices.SortFunc(res, ranking(metric))
+	}
+	if top > 0 && top < len(res) {
+		res = res[:top]
+	}
+	return Runtime(c).Encoder.Encode(res)
+}
+
+func CommandOpeners() *cli.Command {
+	return &cli.Command{
+		Name:     "openers",
+		Category: categoryWordle,
+		Usage:    "Find the best opening words by how they partition the secrets",
+		Description: "Evaluate every guess in the word lists as an opening against the secrets " +
+			"ranked by the metric: entropy, expected (remaining candidates), worst (largest " +
+			"remaining bucket) or wins (playing the opening followed by the strategy). Sets of " +
+			"two or three words are found with a beam search keeping the best sets at each level.",
+		Flags: append(
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:  "secrets",
+					Usage: "the word list, embedded or a file, of secrets",
+					Value: "solutions",
+				},
+				&cli.StringFlag{
+					Name:    "metric",
+					Aliases: []string{"m"},
+					Usage:   "rank by one of entropy, expected, worst or wins",
+					Value:   "entropy",
+				},
+				&cli.IntFlag{
+					Name:    "size",
+					Aliases: []string{"k"},
+					Usage:   "number of words in the opening set",
+					Value:   1,
+				},
+				&cli.IntFlag{
+					Name:    "top",
+					Aliases: []string{"n"},
+					Usage:   "number of openers to show, all if less than one",
+					Value:   10,
+				},
+				&cli.IntFlag{
+					Name:  "beam",
+					Usage: "number of best sets extended at each level and played for wins",
+					Value: 10,
+				},
+				&cli.IntFlag{
+					Name:    "length",
+					Aliases: []string{"l"},
+					Usage:   "word length",
+					Value:   5,
+				},
+				&cli.IntFlag{
+					Name:  "concurrent",
+					Usage: "number of cpus to use for concurrent evaluation",
+					Value: sys.NumCPU(),
+				},
+				&cli.BoolFlag{
+					Name:    "progress",
+					Aliases: []string{"B"},
+					Usage:   "display a progress bar when playing for wins",
+					Value:   false,
+				},
+			},
+			slices.Concat(wordlistFlags(), strategyFlags(), ngramFlags())...,
+		),
+		Action: opening,
+	}
+}
@@ -0,0 +1,193 @@
+package qordle_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestOpenersCommand(t *testing.T) {
+	a := assert.New(t)
+	decode := func(c *cli.Context) []*qordle.Opener {
+		var res []*qordle.Opener
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return res
+	}
+	words := func(res []*qordle.Opener) [][]string {
+		w := make([][]string, len(res))
+		for i := range res {
+			w[i] = res[i].Words
+		}
+		return w
+	}
+	ten := filepath.Join("testdata", "ten.txt")
+	fp, err := os.Open(ten)
+	a.NoError(err)
+	defer fp.Close()
+	secrets, err := qordle.ReadWordlist(fp)
+	a.NoError(err)
+	for _, tt := range []harness{
+		{
+			name: "entropy",
+			args: []string{"openers", "-w", "solutions", "-n", "3"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal([][]string{{"raise"}, {"slate"}, {"crate"}}, words(res))
+				a.Equal(167, res[0].Worst)
+				a.Equal(132, res[0].Buckets)
+				a.InDelta(60.74, res[0].Expected, 0.01)
+				a.InDelta(5.878, res[0].Entropy, 0.001)
+				a.Nil(res[0].Pct)
+				a.Nil(res[0].Mean)
+				return nil
+			},
+		},
+		{
+			name: "worst case three word opening set",
+			args: []string{"openers", "-w", "solutions", "-n", "2", "-k", "3", "--beam", "2", "-m", "worst"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal([][]string{{"raise", "clout", "nymph"}, {"raise", "clout", "downy"}}, words(res))
+				a.Equal(8, res[0].Worst)
+				return nil
+			},
+		},
+		{
+			name: "all openers",
+			args: []string{"openers", "-w", "solutions", "-n", "0", "-m", "expected"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Len(res, 2309)
+				for i := 1; i < len(res); i++ {
+					a.LessOrEqual(res[i-1].Expected, res[i].Expected)
+				}
+				return nil
+			},
+		},
+		{
+			name: "wins",
+			args: []string{"openers", "-w", "solutions", "-n", "2", "--beam", "2", "-m", "wins", "--workers", "1"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal([][]string{{"slate"}, {"raise"}}, words(res))
+				a.InDelta(99.13, *res[0].Pct, 0.01)
+				a.Less(*res[0].Mean, *res[1].Mean)
+				return nil
+			},
+		},
+		{
+			name: "csv without the win metrics",
+			args: []string{"openers", "-w", "solutions", "-n", "1"},
+			before: func(c *cli.Context) error {
+				enc, err := qordle.NewEncoder(c.App.Writer, "csv")
+				qordle.Runtime(c).Encoder = enc
+				return err
+			},
+			after: func(c *cli.Context) error {
+				records, err := csv.NewReader(c.App.Writer.(io.Reader)).ReadAll()
+				a.NoError(err)
+				a.Equal([][]string{
+					{"words", "expected", "entropy", "worst", "buckets", "pct", "mean"},
+					{"raise", "60.74", "5.878", "167", "132", "", ""},
+				}, records)
+				return nil
+			},
+		},
+		{
+			name: "csv with the win metrics",
+			args: []string{"openers", "-w", "solutions", "-n", "1", "--beam", "2", "-m", "wins", "--workers", "1"},
+			before: func(c *cli.Context) error {
+				enc, err := qordle.NewEncoder(c.App.Writer, "csv")
+				qordle.Runtime(c).Encoder = enc
+				return err
+			},
+			after: func(c *cli.Context) error {
+				records, err := csv.NewReader(c.App.Writer.(io.Reader)).ReadAll()
+				a.NoError(err)
+				a.Len(records, 2)
+				a.Equal("slate", records[1][0])
+				a.Equal("99.13", records[1][5])
+				a.NotEmpty(records[1][6])
+				return nil
+			},
+		},
+		{
+			name: "wins counts every guess of the opening",
+			args: []string{"openers", "-w", "solutions", "-n", "1", "-k", "3", "--beam", "1", "-m", "wins", "--workers", "1"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Len(res[0].Words, 3)
+				a.Greater(*res[0].Mean, 3.0)
+				return nil
+			},
+		},
+		{
+			name: "long words",
+			args: []string{
+				"openers", "-W", ten, "--secrets", ten, "-l", "10", "-n", "1", "-k", "2", "--beam", "1",
+			},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Len(res, 1)
+				// the buckets are the distinct pairs of patterns of the opening for each secret
+				buckets := make(map[string]int)
+				for _, secret := range secrets {
+					scores, err := qordle.Score(secret, res[0].Words...)
+					a.NoError(err)
+					buckets[strings.Join(scores, " ")]++
+				}
+				a.Equal(len(buckets), res[0].Buckets)
+				var worst int
+				for _, n := range buckets {
+					worst = max(worst, n)
+				}
+				a.Equal(worst, res[0].Worst)
+				return nil
+			},
+		},
+		{
+			name: "unknown metric",
+			args: []string{"openers", "-m", "foobar"},
+			err:  "unknown metric `foobar`",
+		},
+		{
+			name: "invalid size",
+			args: []string{"openers", "-k", "4"},
+			err:  "size must be between 1 and 3",
+		},
+		{
+			name: "invalid length",
+			args: []string{"openers", "-l", "11"},
+			err:  "length must not exceed 10",
+		},
+		{
+			name: "empty dictionary",
+			args: []string{"openers", "-l", "9", "-w", "solutions"},
+			err:  "empty dictionary",
+		},
+		{
+			name: "invalid secrets",
+			args: []string{"openers", "--secrets", "foobar"},
+			err:  "invalid wordlist `foobar`",
+		},
+		{
+			name: "invalid strategy",
+			args: []string{"openers", "-w", "solutions", "-m", "wins", "-s", "foobar"},
+			err:  "unknown strategy `foobar`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandOpeners)
+		})
+	}
+}
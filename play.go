@@ -1,11 +1,20 @@
 package qordle
 
 import (
+	"context"
 	"errors"
+	"hash/fnv"
 	"io"
+	"math/rand/v2"
+	sys "runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cheggaaa/pb/v3"
+	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,11 +22,64 @@ import (
 const rounds int = 3
 
 type Scoreboard struct {
-	Target     string   `json:"target"`
-	Strategy   string   `json:"strategy"`
-	Dictionary int      `json:"dictionary"`
-	Rounds     []*Round `json:"rounds"`
-	Elapsed    int64    `json:"elapsed"`
+	Target     string     `json:"target"`
+	Strategy   string     `json:"strategy"`
+	Dictionary int        `json:"dictionary"`
+	Rounds     []*Round   `json:"rounds"`
+	Elapsed    int64      `json:"elapsed"`
+	Lies       int        `json:"lies,omitempty"`
+	Selection  *Selection `json:"selection,omitempty"`
+}
+
+// Selection records how the secrets were chosen so a run can be reproduced
+type Selection struct {
+	From    string   `json:"from"`
+	Date    string   `json:"date,omitempty"`
+	All     bool     `json:"all"`
+	Sample  int      `json:"sample,omitempty"`
+	Seed    uint64   `json:"seed,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	History string   `json:"history,omitempty"`
+}
+
+// guesses is the number of words played, more than the rounds if the game used an opening
+func (s *Scoreboard) guesses() int {
+	if n := len(s.Rounds); n > 0 {
+		return max(n, len(s.Rounds[n-1].Words))
+	}
+	return 0
+}
+
+func (s *Scoreboard) Header() []string {
+	return []string{"target", "strategy", "dictionary", "rounds", "success", "scores", "elapsed"}
+}
+
+func (s *Scoreboard) Rows() [][]string {
+	var scores []string
+	var success bool
+	if n := len(s.Rounds); n > 0 {
+		scores, success = s.Rounds[n-1].Scores, s.Rounds[n-1].Success
+	}
+	return [][]string{{
+		s.Target,
+		s.Strategy,
+		strconv.Itoa(s.Dictionary),
+		strconv.Itoa(s.guesses()),
+		strconv.FormatBool(success),
+		strings.Join(scores, " "),
+		strconv.FormatInt(s.Elapsed, 10),
+	}}
+}
+
+// Render the guesses of the final round as tiles followed by a blank line
+func (s *Scoreboard) Render(w io.Writer, tiles *Tiles) error {
+	if n := len(s.Rounds); n > 0 {
+		if err := tiles.Render(w, s.Target, s.Rounds[n-1].Words...); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
 }
 
 type Round struct {
@@ -29,9 +91,12 @@ type Round struct {
 
 type Game struct {
 	start      string
+	opening    []string
 	strategy   Strategy
 	dictionary Dictionary
 	rounds     int
+	lies       int
+	seed       uint64
 }
 
 // Option provides a configuration mechanism for a Game
@@ -53,6 +118,13 @@ func WithStart(start string) Option {
 	}
 }
 
+// WithOpening is the fixed sequence of first words to use, taking precedence over the start word
+func WithOpening(words ...string) Option {
+	return func(g *Game) {
+		g.opening = words
+	}
+}
+
 // WithDictionary is the dictionary to use
 func WithDictionary(dictionary Dictionary) Option {
 	return func(g *Game) {
@@ -73,27 +145,80 @@ func WithRounds(rounds int) Option {
 	}
 }
 
+// WithLies is the number of tiles in each score with a false mark, as in Fibble, chosen
+// reproducibly by the seed
+func WithLies(lies int, seed uint64) Option {
+	return func(g *Game) {
+		g.lies = lies
+		g.seed = seed
+	}
+}
+
+// lie returns the marks with exactly `lies` of them, chosen at random, changed to another mark
+func lie(rng *rand.Rand, marks Marks, lies int) Marks {
+	res := slices.Clone(marks)
+	for _, i := range rng.Perm(len(res))[:min(lies, len(res))] {
+		res[i] = (res[i] + Mark(1+rng.IntN(2))) % 3
+	}
+	return res
+}
+
+// score the guesses against the secret with the lies, if any, the host tells
+func (g *Game) score(rng *rand.Rand, secret string, guesses ...string) ([]string, error) {
+	if g.lies == 0 {
+		return Score(secret, guesses...)
+	}
+	checks, err := Check(secret, guesses...)
+	if err != nil {
+		return nil, err
+	}
+	scores := make([]string, len(checks))
+	for i := range checks {
+		scores[i] = marked(guesses[i], lie(rng, checks[i], g.lies))
+	}
+	return scores, nil
+}
+
 // Play the game for the secret
-func (g *Game) Play(secret string) (*Scoreboard, error) {
+func (g *Game) Play(ctx context.Context, secret string) (*Scoreboard, error) {
 	if g.strategy == nil {
 		return nil, errors.New("missing strategy")
 	}
+	if g.lies < 0 {
+		return nil, errors.New("lies must not be negative")
+	}
 	dictionary := Filter(g.dictionary, Length(len(secret)), IsLower())
 	if len(dictionary) == 0 {
 		return nil, errors.New("empty dictionary")
 	}
+	if len(g.opening) > 0 {
+		opening := g.opening
+		// the opening ends early if it happens to guess the secret
+		if i := slices.Index(opening, secret); i >= 0 {
+			opening = opening[:i+1]
+		}
+		return g.play(ctx, dictionary, secret, slices.Clone(opening))
+	}
 	start := g.start
 	if start == "" {
-		start = g.strategy.Apply(dictionary)[0]
+		words, err := ApplyContext(ctx, g.strategy, dictionary, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(words) == 0 {
+			return nil, errors.New("empty dictionary")
+		}
+		start = words[0]
 	}
-	return g.play(dictionary, secret, []string{start})
+	return g.play(ctx, dictionary, secret, []string{start})
 }
 
-func (g *Game) play(dictionary Dictionary, secret string, words []string) (*Scoreboard, error) {
+func (g *Game) play(ctx context.Context, dictionary Dictionary, secret string, words []string) (*Scoreboard, error) {
 	scoreboard := &Scoreboard{
 		Target:     secret,
 		Strategy:   g.strategy.String(),
 		Dictionary: len(dictionary),
+		Lies:       g.lies,
 	}
 	defer func(t time.Time) {
 		scoreboard.Elapsed = time.Since(t).Milliseconds()
@@ -103,22 +228,41 @@ func (g *Game) play(dictionary Dictionary, secret string, words []string) (*Scor
 	if r <= 0 {
 		r = rounds
 	}
-	n := len(secret) * r
+	// each guess is scored once so a lie stays the same in later rounds
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(secret))
+	rng := rand.New(rand.NewPCG(g.seed, h.Sum64())) //nolint:gosec // G404: reproducibility matters, not security
+
+	// the dictionary is filtered by the scores not yet applied, all of them for an opening
+	var scores []string
+	n, applied := len(secret)*r, 0
 	for len(scoreboard.Rounds) < n {
-		scores, err := Score(secret, words...)
+		next, err := g.score(rng, secret, words[len(scores):]...)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, next...)
+		guess, err := GuessLies(g.lies, scores[applied:]...)
+		applied = len(scores)
 		if err != nil {
 			return nil, err
 		}
-		guess, err := Guess(scores[len(scores)-1])
+		var knowledge *Knowledge
+		if g.lies == 0 {
+			knowledge, err = NewKnowledge(scores...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		dictionary, err = ApplyContext(ctx, g.strategy, Filter(dictionary, guess), knowledge)
 		if err != nil {
 			return nil, err
 		}
-		dictionary = g.strategy.Apply(Filter(dictionary, guess))
 
 		round := &Round{
 			Dictionary: len(dictionary),
-			Scores:     scores,
-			Words:      words,
+			Scores:     slices.Clone(scores),
+			Words:      slices.Clone(words),
 		}
 		scoreboard.Rounds = append(scoreboard.Rounds, round)
 
@@ -135,25 +279,154 @@ func (g *Game) play(dictionary Dictionary, secret string, words []string) (*Scor
 	return scoreboard, nil
 }
 
+// selected returns the secrets chosen by the selection flags or nil if no selection was requested
+func selected(c *cli.Context) (Dictionary, *Selection, error) {
+	if !c.IsSet("from") && !c.IsSet("sample") && !c.Bool("all") && !c.IsSet("date") {
+		return nil, nil, nil
+	}
+	if c.IsSet("sample") && c.Bool("all") {
+		return nil, nil, errors.New("only one of --sample or --all may be specified")
+	}
+	if c.NArg() > 0 {
+		return nil, nil, errors.New("secrets cannot be both provided and selected")
+	}
+	if c.IsSet("date") {
+		if c.IsSet("from") || c.IsSet("sample") || c.Bool("all") {
+			return nil, nil, errors.New("the daily puzzle cannot be combined with other selections")
+		}
+		d, err := daily(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Dictionary{d.Solution}, &Selection{From: "daily", Date: d.Date}, nil
+	}
+	selection := &Selection{
+		From:    c.String("from"),
+		All:     !c.IsSet("sample"),
+		Exclude: c.StringSlice("exclude"),
+		History: c.Path("history"),
+	}
+	secrets, err := resolve(c, selection.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err = excluding(c, secrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !selection.All {
+		selection.Sample, selection.Seed = c.Int("sample"), c.Uint64("seed")
+		secrets = Sample(secrets, selection.Sample, selection.Seed)
+	}
+	return secrets, selection, nil
+}
+
+// playall plays the secrets concurrently encoding the scoreboards in the order of the secrets
+func playall(
+	ctx context.Context, game *Game, secrets []string, selection *Selection,
+	concurrent int, bar *pb.ProgressBar, enc Encoder,
+) error {
+	type result struct {
+		index int
+		board *Scoreboard
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range secrets {
+			select {
+			case <-ctx.Done():
+				return
+			case indices <- i:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	results := make(chan result)
+	for range workers(concurrent, len(secrets)) {
+		wg.Go(func() {
+			for i := range indices {
+				board, err := game.Play(ctx, secrets[i])
+				if err != nil {
+					cancel(err)
+					return
+				}
+				board.Selection = selection
+				select {
+				case <-ctx.Done():
+					return
+				case results <- result{index: i, board: board}:
+				}
+			}
+		})
+	}
+	go func() {
+		defer close(results)
+		wg.Wait()
+	}()
+
+	// buffer the out of order scoreboards until the next in order is available
+	next, pending := 0, make(map[int]*Scoreboard)
+	for res := range results {
+		bar.Increment()
+		pending[res.index] = res.board
+		for board, ok := pending[next]; ok; board, ok = pending[next] {
+			delete(pending, next)
+			next++
+			if err := enc.Encode(board); err != nil {
+				cancel(err)
+			}
+		}
+	}
+	return context.Cause(ctx)
+}
+
 func play(c *cli.Context) error {
 	dictionary, strategy, err := prepare(c, "possible", "solutions")
 	if err != nil {
 		return err
 	}
-	secrets := c.Args().Slice()
-	if len(secrets) == 0 {
-		secrets, err = read(c.App.Reader)
+	secrets, selection, err := selected(c)
+	if err != nil {
+		return err
+	}
+	if selection == nil {
+		switch {
+		case c.NArg() > 0:
+			secrets = c.Args().Slice()
+		default:
+			secrets, err = read(c.App.Reader)
+			if err != nil {
+				return err
+			}
+		}
+		var kept Dictionary
+		kept, err = excluding(c, secrets)
 		if err != nil {
 			return err
 		}
+		// the secrets were asked for explicitly so never drop one silently
+		for _, secret := range secrets.Difference(kept) {
+			log.Warn().Str("secret", secret).Msg("excluded")
+		}
+		secrets = kept
+	}
+	dictionary, err = excluding(c, dictionary)
+	if err != nil {
+		return err
 	}
-	enc := Runtime(c).Encoder
 
 	game := NewGame(
 		WithStrategy(strategy),
 		WithDictionary(dictionary),
 		WithStart(c.String("start")),
-		WithRounds(c.Int("rounds")))
+		WithRounds(c.Int("rounds")),
+		WithLies(c.Int("lies"), c.Uint64("seed")))
 
 	writer := io.Discard
 	if c.Bool("progress") {
@@ -162,18 +435,7 @@ func play(c *cli.Context) error {
 	bar := pb.New(len(secrets)).SetWriter(writer).Start()
 	defer bar.Finish()
 
-	var board *Scoreboard
-	for i := range secrets {
-		bar.Increment()
-		board, err = game.Play(secrets[i])
-		if err != nil {
-			return err
-		}
-		if err = enc.Encode(board); err != nil {
-			return err
-		}
-	}
-	return nil
+	return playall(c.Context, game, secrets, selection, c.Int("concurrent"), bar, Runtime(c).Encoder)
 }
 
 func CommandPlay() *cli.Command {
@@ -181,7 +443,7 @@ func CommandPlay() *cli.Command {
 		Name:     "play",
 		Category: categoryWordle,
 		Usage:    "Play wordle automatically",
-		Flags: append(
+		Flags: slices.Concat(
 			[]cli.Flag{
 				&cli.StringFlag{
 					Name:    "start",
@@ -200,8 +462,40 @@ func CommandPlay() *cli.Command {
 					Usage:   "max rounds not to exceed `rounds` * len(secret)",
 					Value:   rounds,
 				},
+				&cli.IntFlag{
+					Name:  "concurrent",
+					Usage: "number of secrets to play concurrently",
+					Value: sys.NumCPU(),
+				},
+				&cli.StringFlag{
+					Name:  "from",
+					Usage: "the word list, embedded or a file, from which to select secrets",
+					Value: "possible",
+				},
+				&cli.IntFlag{
+					Name:    "sample",
+					Aliases: []string{"n"},
+					Usage:   "number of secrets to sample from the word list",
+				},
+				&cli.Uint64Flag{
+					Name:  "seed",
+					Usage: "seed for sampling the secrets and the lies",
+					Value: 1,
+				},
+				&cli.IntFlag{
+					Name:  "lies",
+					Usage: "number of tiles in each score given a false mark, as in Fibble",
+				},
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "play every secret in the word list",
+				},
+				&cli.StringFlag{
+					Name:  "date",
+					Usage: "play the daily puzzle for `today` or YYYY-MM-DD",
+				},
 			},
-			append(wordlistFlags(), strategyFlags()...)...,
+			wordlistFlags(), exclusionFlags(), strategyFlags(), ngramFlags(), dailyFlags(),
 		),
 		Action: play,
 	}
@@ -1,8 +1,13 @@
 package qordle_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -79,7 +84,7 @@ func TestGame(t *testing.T) {
 				qordle.WithStrategy(tt.strategy),
 				qordle.WithDictionary(dt),
 				qordle.WithStart(tt.start))
-			scoreboard, err := game.Play(tt.secret)
+			scoreboard, err := game.Play(context.Background(), tt.secret)
 			if tt.errStrategy != "" {
 				a.Error(err)
 				a.Equal(tt.errStrategy, err.Error())
@@ -105,8 +110,70 @@ func TestGame(t *testing.T) {
 	}
 }
 
+func TestGameContext(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, start := range []string{"", "soare"} {
+		game := qordle.NewGame(
+			qordle.WithStrategy(qordle.NewChain(new(qordle.Elimination), new(qordle.Frequency))),
+			qordle.WithDictionary(dt),
+			qordle.WithStart(start))
+		scoreboard, err := game.Play(ctx, "shine")
+		a.ErrorIs(err, context.Canceled)
+		a.Nil(scoreboard)
+	}
+}
+
+func TestGameOpening(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+	game := qordle.NewGame(
+		qordle.WithStrategy(new(qordle.Frequency)),
+		qordle.WithDictionary(dt),
+		qordle.WithStart("soare"),
+		qordle.WithOpening("raise", "clout"))
+	for secret, words := range map[string][]string{
+		"raise": {"raise"},
+		"clout": {"raise", "clout"},
+		"nymph": {"raise", "clout", "nymph"},
+	} {
+		scoreboard, err := game.Play(context.Background(), secret)
+		a.NoError(err)
+		winner := scoreboard.Rounds[len(scoreboard.Rounds)-1]
+		a.True(winner.Success)
+		a.Equal(words, winner.Words)
+	}
+
+	// the guesses of the opening are counted even though they are played in one round
+	game = qordle.NewGame(
+		qordle.WithStrategy(new(qordle.Frequency)),
+		qordle.WithDictionary(dt),
+		qordle.WithOpening("raise", "clout", "nymph"))
+	scoreboard, err := game.Play(context.Background(), "vapid")
+	a.NoError(err)
+	a.Len(scoreboard.Rounds, 2)
+	a.Equal([]string{"raise", "clout", "nymph", "vapid"}, scoreboard.Rounds[1].Words)
+	var buf bytes.Buffer
+	enc, err := qordle.NewEncoder(&buf, "csv")
+	a.NoError(err)
+	a.NoError(enc.Encode(scoreboard))
+	a.NoError(qordle.Flush(enc))
+	records, err := csv.NewReader(&buf).ReadAll()
+	a.NoError(err)
+	a.Equal("rounds", records[0][3])
+	a.Equal("4", records[1][3])
+}
+
 func TestPlayCommand(t *testing.T) {
 	a := assert.New(t)
+	excluded := filepath.Join(t.TempDir(), "excluded.txt")
+	a.NoError(os.WriteFile(excluded, []byte("TABLE\naback\n"), 0o600))
 
 	decode := func(c *cli.Context) *qordle.Round {
 		var res qordle.Scoreboard
@@ -159,6 +226,123 @@ func TestPlayCommand(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "canceled context",
+			args: []string{"play", "-s", "position", "--start", "soare", "table"},
+			err:  context.Canceled.Error(),
+			context: func(ctx context.Context) context.Context {
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				return ctx
+			},
+		},
+		{
+			name: "bounded workers",
+			args: []string{"play", "-s", "position", "--workers", "1", "--start", "soare", "table"},
+			after: func(c *cli.Context) error {
+				round := decode(c)
+				a.True(round.Success)
+				return nil
+			},
+		},
+		{
+			name: "concurrent games in order",
+			args: []string{"play", "--concurrent", "4", "--start", "soare", "table", "shine", "train", "brain", "raise"},
+			after: func(c *cli.Context) error {
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				for _, secret := range []string{"table", "shine", "train", "brain", "raise"} {
+					var res qordle.Scoreboard
+					a.NoError(dec.Decode(&res))
+					a.Equal(secret, res.Target)
+				}
+				return nil
+			},
+		},
+		{
+			name: "concurrent games with an error",
+			args: []string{"play", "--concurrent", "2", "table", "123456", "train"},
+			err:  "empty dictionary",
+		},
+		{
+			name: "sample secrets reproducibly",
+			args: []string{"play", "--start", "soare", "--sample", "3", "--seed", "7", "--exclude", excluded},
+			after: func(c *cli.Context) error {
+				dictionary, err := qordle.Read("possible")
+				a.NoError(err)
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				for _, secret := range qordle.Sample(dictionary, 3, 7) {
+					var res qordle.Scoreboard
+					a.NoError(dec.Decode(&res))
+					a.Equal(secret, res.Target)
+					a.Equal(&qordle.Selection{
+						From: "possible", Sample: 3, Seed: 7, Exclude: []string{excluded}}, res.Selection)
+				}
+				return nil
+			},
+		},
+		{
+			name: "first error cancels playing all secrets",
+			args: []string{"play", "--start", "soare", "--all", "--from", "solutions", "--exclude", excluded},
+			before: func(c *cli.Context) error {
+				qordle.Runtime(c).Encoder = json.NewEncoder(new(errWriter))
+				return nil
+			},
+			err: ErrEncoding.Error(),
+		},
+		{
+			name: "exclude secrets from the arguments",
+			args: []string{"play", "--start", "soare", "--exclude", excluded, "table", "shine"},
+			after: func(c *cli.Context) error {
+				var res qordle.Scoreboard
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal("shine", res.Target)
+				a.Nil(res.Selection)
+				a.Error(dec.Decode(&res))
+				return nil
+			},
+		},
+		{
+			name: "exclude a secret from the arguments",
+			args: []string{"play", "--start", "soare", "--exclude", "table", "table", "shine"},
+			after: func(c *cli.Context) error {
+				var res qordle.Scoreboard
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal("shine", res.Target)
+				a.Error(dec.Decode(&res))
+				return nil
+			},
+		},
+		{
+			name: "sample and all",
+			args: []string{"play", "--sample", "3", "--all"},
+			err:  "only one of --sample or --all may be specified",
+		},
+		{
+			name: "selected and provided secrets",
+			args: []string{"play", "--sample", "3", "table"},
+			err:  "secrets cannot be both provided and selected",
+		},
+		{
+			name: "select secrets from a file",
+			args: []string{"play", "--start", "soare", "--all", "--from", excluded},
+			after: func(c *cli.Context) error {
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				for _, secret := range []string{"table", "aback"} {
+					var res qordle.Scoreboard
+					a.NoError(dec.Decode(&res))
+					a.Equal(secret, res.Target)
+					a.Equal(&qordle.Selection{From: excluded, All: true}, res.Selection)
+				}
+				return nil
+			},
+		},
+		{
+			name: "invalid selection wordlist",
+			args: []string{"play", "--from", "foobar"},
+			err:  "invalid wordlist `foobar`",
+		},
 		{
 			name: "failed to find secret",
 			args: []string{"play", "12345"},
@@ -278,6 +462,33 @@ func TestPlayCommand(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "lying host",
+			args: []string{"play", "-s", "frequency", "--start", "soare", "--lies", "1", "--seed", "3", "table"},
+			after: func(c *cli.Context) error {
+				var res qordle.Scoreboard
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal(1, res.Lies)
+				round := res.Rounds[len(res.Rounds)-1]
+				a.True(round.Success)
+				truth, err := qordle.Score("table", round.Words...)
+				a.NoError(err)
+				for i := range truth {
+					a.NotEqual(truth[i], round.Scores[i])
+				}
+				// each lie is told once and repeated in every later round
+				for _, r := range res.Rounds {
+					a.Equal(round.Scores[:len(r.Scores)], r.Scores)
+				}
+				return nil
+			},
+		},
+		{
+			name: "negative lies",
+			args: []string{"play", "--start", "soare", "--lies", "-1", "table"},
+			err:  "lies must not be negative",
+		},
 		{
 			name: "encoding error",
 			args: []string{"play", "-s", "bigram", "-S", "aahed"},
@@ -311,7 +522,7 @@ func BenchmarkPlay(b *testing.B) {
 		b.Run("secret::"+secret, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				var board *qordle.Scoreboard
-				board, err = game.Play(secret)
+				board, err = game.Play(context.Background(), secret)
 				a.NoError(err)
 				a.Greater(len(board.Rounds), 0)
 			}
@@ -0,0 +1,232 @@
+package qordle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pluginTimeout is the default time allowed for a plugin to respond
+const pluginTimeout = 10 * time.Second
+
+// PluginRequest is written as a single line of JSON to the plugin's stdin
+type PluginRequest struct {
+	Strategy string   `json:"strategy"`
+	Words    []string `json:"words"`
+	Patterns []string `json:"patterns"`
+}
+
+// PluginResponse is read as a single line of JSON from the plugin's stdout
+//
+// A plugin returns either the ordered words or a score for each word, higher
+// scores sorting first, or an error message.
+type PluginResponse struct {
+	Words  []string           `json:"words,omitempty"`
+	Scores map[string]float64 `json:"scores,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// PluginConfig describes a plugin in a configuration file
+type PluginConfig struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Command     []string `json:"command"`
+	Timeout     string   `json:"timeout,omitempty"`
+}
+
+// Plugin is a Strategy implemented by an external command
+type Plugin struct {
+	name        string
+	description string
+	command     []string
+	timeout     time.Duration
+	tables      *Tables
+}
+
+// PluginOption provides a configuration mechanism for a Plugin
+type PluginOption func(*Plugin)
+
+// WithPluginTimeout is the maximum time allowed for the plugin to respond
+func WithPluginTimeout(timeout time.Duration) PluginOption {
+	return func(p *Plugin) {
+		p.timeout = timeout
+	}
+}
+
+// WithPluginDescription is the human-readable description of the plugin
+func WithPluginDescription(description string) PluginOption {
+	return func(p *Plugin) {
+		p.description = description
+	}
+}
+
+// NewPlugin creates a new plugin strategy executing the command
+func NewPlugin(name string, command []string, opts ...PluginOption) (*Plugin, error) {
+	if name == "" {
+		return nil, errors.New("missing plugin name")
+	}
+	if len(command) == 0 {
+		return nil, fmt.Errorf("missing command for plugin `%s`", name)
+	}
+	p := &Plugin{
+		name:        name,
+		description: fmt.Sprintf("External strategy running `%s`", strings.Join(command, " ")),
+		command:     command,
+		timeout:     pluginTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// ParsePlugin parses a plugin from a `name=command [arg ...]` specification
+func ParsePlugin(spec string) (*Plugin, error) {
+	name, command, ok := strings.Cut(spec, "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid plugin `%s`", spec)
+	}
+	return NewPlugin(strings.TrimSpace(name), strings.Fields(command))
+}
+
+// ReadPlugins reads the plugins from a JSON configuration
+func ReadPlugins(r io.Reader) ([]*Plugin, error) {
+	var configs struct {
+		Plugins []PluginConfig `json:"plugins"`
+	}
+	if err := json.NewDecoder(r).Decode(&configs); err != nil {
+		return nil, err
+	}
+	plugins := make([]*Plugin, len(configs.Plugins))
+	for i, config := range configs.Plugins {
+		var opts []PluginOption
+		if config.Description != "" {
+			opts = append(opts, WithPluginDescription(config.Description))
+		}
+		if config.Timeout != "" {
+			timeout, err := time.ParseDuration(config.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for plugin `%s`: %w", config.Name, err)
+			}
+			opts = append(opts, WithPluginTimeout(timeout))
+		}
+		plugin, err := NewPlugin(config.Name, config.Command, opts...)
+		if err != nil {
+			return nil, err
+		}
+		plugins[i] = plugin
+	}
+	return plugins, nil
+}
+
+func (s *Plugin) String() string {
+	return s.name
+}
+
+// Description of the plugin
+func (s *Plugin) Description() string {
+	return s.description
+}
+
+// Tables sets the letter tables used to order words with the same score
+func (s *Plugin) Tables(tables *Tables) {
+	s.tables = tables
+}
+
+func (s *Plugin) Apply(words Dictionary) Dictionary {
+	return s.ApplyKnowledge(words, nil)
+}
+
+func (s *Plugin) ApplyKnowledge(words Dictionary, knowledge *Knowledge) Dictionary {
+	res, err := s.ApplyContext(context.Background(), words, knowledge)
+	if err != nil {
+		log.Error().Err(err).Str("plugin", s.name).Msg("plugin")
+		return nil
+	}
+	return res
+}
+
+// verify the words returned by the plugin are among the words requested and returned only once
+func (s *Plugin) verify(words, res Dictionary) (Dictionary, error) {
+	requested := make(map[string]bool, len(words))
+	for _, word := range words {
+		requested[word] = true
+	}
+	returned := make(map[string]bool, len(res))
+	for _, word := range res {
+		switch {
+		case !requested[word]:
+			return nil, fmt.Errorf("plugin `%s` returned the unknown word `%s`", s.name, word)
+		case returned[word]:
+			return nil, fmt.Errorf("plugin `%s` returned the word `%s` more than once", s.name, word)
+		}
+		returned[word] = true
+	}
+	return res, nil
+}
+
+func (s *Plugin) ApplyContext(ctx context.Context, words Dictionary, knowledge *Knowledge) (Dictionary, error) {
+	if len(words) == 0 {
+		return words, nil
+	}
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	patterns := knowledge.Patterns()
+	if patterns == nil {
+		patterns = []string{}
+	}
+	var stdin, stdout, stderr bytes.Buffer
+	if err := json.NewEncoder(&stdin).Encode(&PluginRequest{
+		Strategy: s.name,
+		Words:    words,
+		Patterns: patterns,
+	}); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...) //nolint:gosec // the command is configured by the user
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		switch {
+		case parent.Err() != nil:
+			return nil, parent.Err()
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("plugin `%s` timed out after %s", s.name, s.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin `%s` failed: %w: %s", s.name, err, msg)
+		}
+		return nil, fmt.Errorf("plugin `%s` failed: %w", s.name, err)
+	}
+
+	line, err := stdout.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var res PluginResponse
+	if err = json.Unmarshal(line, &res); err != nil {
+		return nil, fmt.Errorf("plugin `%s` returned an invalid response: %w", s.name, err)
+	}
+	switch {
+	case res.Error != "":
+		return nil, fmt.Errorf("plugin `%s` returned an error: %s", s.name, res.Error)
+	case res.Scores != nil:
+		return s.verify(words, mkdictf(res.Scores, s.tables.orDefault().Frequencies, func(i, j float64) bool {
+			return i > j
+		}))
+	default:
+		return s.verify(words, res.Words)
+	}
+}
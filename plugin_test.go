@@ -0,0 +1,312 @@
+package qordle_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bzimmer/qordle"
+)
+
+// TestPluginHelper is not a real test but the external command run by the plugin tests
+func TestPluginHelper(t *testing.T) {
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 {
+		return
+	}
+	var req qordle.PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	switch args[1] {
+	case "reverse":
+		words := slices.Clone(req.Words)
+		slices.Reverse(words)
+		_ = enc.Encode(qordle.PluginResponse{Words: words})
+	case "scores":
+		scores := make(map[string]float64, len(req.Words))
+		for i, word := range req.Words {
+			scores[word] = float64(i)
+		}
+		_ = enc.Encode(qordle.PluginResponse{Scores: scores})
+	case "tied":
+		scores := make(map[string]float64, len(req.Words))
+		for _, word := range req.Words {
+			scores[word] = 1
+		}
+		_ = enc.Encode(qordle.PluginResponse{Scores: scores})
+	case "patterns":
+		_ = enc.Encode(qordle.PluginResponse{Error: strings.Join(req.Patterns, " ")})
+	case "unknown":
+		_ = enc.Encode(qordle.PluginResponse{Words: append(req.Words, "zzzzz")})
+	case "duplicate":
+		_ = enc.Encode(qordle.PluginResponse{Words: append(req.Words, req.Words[0])})
+	case "unscored":
+		_ = enc.Encode(qordle.PluginResponse{Scores: map[string]float64{"zzzzz": 1}})
+	case "error":
+		_ = enc.Encode(qordle.PluginResponse{Error: "no words for you"})
+	case "garbage":
+		fmt.Fprintln(os.Stdout, "{not json")
+	case "exit":
+		fmt.Fprintln(os.Stderr, "something bad happened")
+		os.Exit(3)
+	case "sleep":
+		time.Sleep(5 * time.Second)
+	}
+	os.Exit(0)
+}
+
+func helper(mode string) []string {
+	return []string{os.Args[0], "-test.run=^TestPluginHelper$", "--", mode}
+}
+
+func TestPlugin(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"brain", "raise", "sport", "maths"}
+	for _, tt := range []struct {
+		name, mode string
+		patterns   []string
+		words      qordle.Dictionary
+		result     qordle.Dictionary
+		timeout    time.Duration
+		err        string
+	}{
+		{
+			name:   "ordered words",
+			mode:   "reverse",
+			words:  words,
+			result: qordle.Dictionary{"maths", "sport", "raise", "brain"},
+		},
+		{
+			name:   "scored words",
+			mode:   "scores",
+			words:  words,
+			result: qordle.Dictionary{"maths", "sport", "raise", "brain"},
+		},
+		{
+			name:     "game state",
+			mode:     "patterns",
+			patterns: []string{"t.r.ain", "b.rA.in"},
+			words:    words,
+			err:      "plugin `patterns` returned an error: t.r.ain b.rA.in",
+		},
+		{
+			name:   "empty words",
+			mode:   "error",
+			words:  qordle.Dictionary{},
+			result: qordle.Dictionary{},
+		},
+		{
+			name:  "plugin error",
+			mode:  "error",
+			words: words,
+			err:   "plugin `error` returned an error: no words for you",
+		},
+		{
+			name:  "invalid response",
+			mode:  "garbage",
+			words: words,
+			err:   "plugin `garbage` returned an invalid response",
+		},
+		{
+			name:  "non-zero exit",
+			mode:  "exit",
+			words: words,
+			err:   "plugin `exit` failed: exit status 3: something bad happened",
+		},
+		{
+			name:    "timeout",
+			mode:    "sleep",
+			words:   words,
+			timeout: 100 * time.Millisecond,
+			err:     "plugin `sleep` timed out after 100ms",
+		},
+		{
+			name:  "unknown word",
+			mode:  "unknown",
+			words: words,
+			err:   "plugin `unknown` returned the unknown word `zzzzz`",
+		},
+		{
+			name:  "unknown scored word",
+			mode:  "unscored",
+			words: words,
+			err:   "plugin `unscored` returned the unknown word `zzzzz`",
+		},
+		{
+			name:  "duplicate word",
+			mode:  "duplicate",
+			words: words,
+			err:   "plugin `duplicate` returned the word `brain` more than once",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			var opts []qordle.PluginOption
+			if tt.timeout > 0 {
+				opts = append(opts, qordle.WithPluginTimeout(tt.timeout))
+			}
+			plugin, err := qordle.NewPlugin(tt.mode, helper(tt.mode), opts...)
+			a.NoError(err)
+			a.Equal(tt.mode, plugin.String())
+			knowledge, err := qordle.NewKnowledge(tt.patterns...)
+			a.NoError(err)
+			res, err := qordle.ApplyContext(context.Background(), plugin, tt.words, knowledge)
+			if tt.err != "" {
+				a.ErrorContains(err, tt.err)
+				a.Nil(res)
+				a.Nil(qordle.ApplyKnowledge(plugin, tt.words, knowledge))
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, res)
+			a.Equal(tt.result, qordle.ApplyKnowledge(plugin, tt.words, knowledge))
+		})
+	}
+}
+
+func TestPluginTables(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	plugin, err := qordle.NewPlugin("tied", helper("tied"))
+	a.NoError(err)
+	words := qordle.Dictionary{"jumpy", "raise"}
+	a.Equal(qordle.Dictionary{"raise", "jumpy"}, plugin.Apply(words))
+	qordle.SetTables(plugin, qordle.NewTables(qordle.Dictionary{"jumpy"}))
+	a.Equal(qordle.Dictionary{"jumpy", "raise"}, plugin.Apply(words))
+}
+
+func TestPluginChain(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	plugin, err := qordle.NewPlugin("reverse", helper("reverse"))
+	a.NoError(err)
+	words := qordle.Dictionary{"brain", "maths", "raise", "sport"}
+	a.Equal(qordle.Dictionary{"sport", "raise", "maths", "brain"}, plugin.Apply(words))
+	chain := qordle.NewChain(plugin, new(qordle.Alpha))
+	a.Equal("chain{reverse,alpha}", chain.String())
+	a.Len(chain.Apply(words), len(words))
+}
+
+func TestPluginContext(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	plugin, err := qordle.NewPlugin("sleep", helper("sleep"))
+	a.NoError(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	chain := qordle.NewChain(plugin, new(qordle.Alpha))
+	words, err := qordle.ApplyContext(ctx, chain, qordle.Dictionary{"brain", "raise"}, nil)
+	a.ErrorIs(err, context.DeadlineExceeded)
+	a.Nil(words)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	words, err = qordle.ApplyContext(ctx, plugin, qordle.Dictionary{"brain", "raise"}, nil)
+	a.ErrorIs(err, context.Canceled)
+	a.Nil(words)
+}
+
+func TestParsePlugin(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, spec, err string
+	}{
+		{
+			name: "valid",
+			spec: "ds=python3 -m strategy --fast",
+		},
+		{
+			name: "missing separator",
+			spec: "python3",
+			err:  "invalid plugin `python3`",
+		},
+		{
+			name: "missing name",
+			spec: "=python3",
+			err:  "missing plugin name",
+		},
+		{
+			name: "missing command",
+			spec: "ds= ",
+			err:  "missing command for plugin `ds`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			plugin, err := qordle.ParsePlugin(tt.spec)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				a.Nil(plugin)
+				return
+			}
+			a.NoError(err)
+			a.Equal("ds", plugin.String())
+			a.Equal("External strategy running `python3 -m strategy --fast`", plugin.Description())
+		})
+	}
+}
+
+func TestReadPlugins(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, config, err string
+		plugins           []string
+	}{
+		{
+			name:    "valid",
+			config:  `{"plugins": [{"name": "ds", "description": "data science", "command": ["python3", "ds.py"], "timeout": "2s"}, {"name": "ml", "command": ["ml"]}]}`,
+			plugins: []string{"ds", "ml"},
+		},
+		{
+			name:   "invalid json",
+			config: `{"plugins": [`,
+			err:    "unexpected EOF",
+		},
+		{
+			name:   "invalid timeout",
+			config: `{"plugins": [{"name": "ds", "command": ["python3"], "timeout": "soon"}]}`,
+			err:    "invalid timeout for plugin `ds`",
+		},
+		{
+			name:   "missing command",
+			config: `{"plugins": [{"name": "ds"}]}`,
+			err:    "missing command for plugin `ds`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			plugins, err := qordle.ReadPlugins(strings.NewReader(tt.config))
+			if tt.err != "" {
+				a.ErrorContains(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			names := make([]string, len(plugins))
+			for i := range plugins {
+				names[i] = plugins[i].String()
+			}
+			a.Equal(tt.plugins, names)
+			a.Equal("data science", plugins[0].Description())
+			a.Equal("External strategy running `ml`", plugins[1].Description())
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -38,22 +37,6 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-type strategies struct {
-	strategies qordle.Trie[qordle.Strategy]
-}
-
-func (s strategies) Strategy(prefix string) (qordle.Strategy, error) {
-	strategy := s.strategies.Value(prefix)
-	if strategy != nil {
-		return strategy, nil
-	}
-	return nil, fmt.Errorf("unknown strategy `%s`", prefix)
-}
-
-func (s strategies) Strategies() []string {
-	return s.strategies.Strings()
-}
-
 type harness struct {
 	name, err string
 	args      []string
@@ -65,16 +48,6 @@ type harness struct {
 func newTestApp(tt *harness, cmd *cli.Command) *cli.App {
 	name := strings.ReplaceAll(tt.name, " ", "-")
 
-	trie := qordle.Trie[qordle.Strategy]{}
-	for _, strategy := range []qordle.Strategy{
-		new(qordle.Alpha),
-		new(qordle.Bigram),
-		new(qordle.Frequency),
-		new(qordle.Position),
-	} {
-		trie.Add(strategy.String(), strategy)
-	}
-
 	return &cli.App{
 		Name:      name,
 		HelpName:  name,
@@ -86,11 +59,14 @@ func newTestApp(tt *harness, cmd *cli.Command) *cli.App {
 				qordle.RuntimeKey: &qordle.Rt{
 					Encoder:    json.NewEncoder(c.App.Writer),
 					Start:      time.Now(),
-					Strategies: &strategies{strategies: trie},
+					Strategies: qordle.DefaultRegistry(),
 				},
 			}
 			return nil
 		},
+		After: func(c *cli.Context) error {
+			return qordle.Flush(qordle.Runtime(c).Encoder)
+		},
 		Commands: []*cli.Command{cmd},
 	}
 }
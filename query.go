@@ -0,0 +1,147 @@
+package qordle
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Query describes the words of interest beyond the feedback of a game
+type Query struct {
+	// Contains are the letters which must all be in the word
+	Contains string `json:"contains,omitempty"`
+	// Lacks are the letters which must not be in the word
+	Lacks string `json:"lacks,omitempty"`
+	// Regex is a regular expression the word must match
+	Regex string `json:"regex,omitempty"`
+	// Wildcard is a pattern the whole word must match, `?` for any letter and `*` for any letters
+	Wildcard string `json:"wildcard,omitempty"`
+	// Unique words have no repeated letters
+	Unique bool `json:"unique,omitempty"`
+	// Vowels is the exact number of vowels if not nil
+	Vowels *int   `json:"vowels,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+func queryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "contains",
+			Usage: "only words containing all the `letters`",
+		},
+		&cli.StringFlag{
+			Name:  "lacks",
+			Usage: "only words containing none of the `letters`",
+		},
+		&cli.StringFlag{
+			Name:  "regex",
+			Usage: "only words matching the regular `expression`",
+		},
+		&cli.StringFlag{
+			Name:  "wildcard",
+			Usage: "only words matching the `pattern`, `?` for any letter and `*` for any letters",
+		},
+		&cli.BoolFlag{
+			Name:  "unique",
+			Usage: "only words without repeated letters",
+		},
+		&cli.IntFlag{
+			Name:  "vowels",
+			Usage: "only words with exactly `n` vowels",
+		},
+		&cli.StringFlag{
+			Name:  "prefix",
+			Usage: "only words starting with the prefix",
+		},
+		&cli.StringFlag{
+			Name:  "suffix",
+			Usage: "only words ending with the suffix",
+		},
+	}
+}
+
+// NewQuery creates a query from the url values using the names of the query flags
+func NewQuery(values url.Values) (*Query, error) {
+	q := &Query{
+		Contains: values.Get("contains"),
+		Lacks:    values.Get("lacks"),
+		Regex:    values.Get("regex"),
+		Wildcard: values.Get("wildcard"),
+		Prefix:   values.Get("prefix"),
+		Suffix:   values.Get("suffix"),
+	}
+	if unique := values.Get("unique"); unique != "" {
+		b, err := strconv.ParseBool(unique)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unique `%s`", unique)
+		}
+		q.Unique = b
+	}
+	if vowels := values.Get("vowels"); vowels != "" {
+		n, err := strconv.Atoi(vowels)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vowels `%s`", vowels)
+		}
+		q.Vowels = &n
+	}
+	return q, nil
+}
+
+// queried returns the query described by the query flags
+func queried(c *cli.Context) *Query {
+	q := &Query{
+		Contains: c.String("contains"),
+		Lacks:    c.String("lacks"),
+		Regex:    c.String("regex"),
+		Wildcard: c.String("wildcard"),
+		Unique:   c.Bool("unique"),
+		Prefix:   c.String("prefix"),
+		Suffix:   c.String("suffix"),
+	}
+	if c.IsSet("vowels") {
+		n := c.Int("vowels")
+		q.Vowels = &n
+	}
+	return q
+}
+
+// Filters returns the filters matching the words of the query
+func (q *Query) Filters() ([]FilterFunc, error) {
+	var fns []FilterFunc
+	if q.Contains != "" {
+		fns = append(fns, Contains(q.Contains))
+	}
+	if q.Lacks != "" {
+		fns = append(fns, Lacks(q.Lacks))
+	}
+	if q.Regex != "" {
+		fn, err := Regex(q.Regex)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, fn)
+	}
+	if q.Wildcard != "" {
+		fn, err := Wildcard(q.Wildcard)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, fn)
+	}
+	if q.Unique {
+		fns = append(fns, Unique())
+	}
+	if q.Vowels != nil {
+		fns = append(fns, Vowels(*q.Vowels))
+	}
+	if q.Prefix != "" {
+		fns = append(fns, Prefix(q.Prefix))
+	}
+	if q.Suffix != "" {
+		fns = append(fns, Suffix(q.Suffix))
+	}
+	return fns, nil
+}
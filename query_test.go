@@ -0,0 +1,79 @@
+package qordle_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"crane", "chase", "geese", "nymph", "spree", "crate", "react"}
+	for _, tt := range []struct {
+		name, err string
+		values    url.Values
+		result    qordle.Dictionary
+	}{
+		{
+			name:   "empty",
+			values: url.Values{},
+			result: words,
+		},
+		{
+			name: "all",
+			values: url.Values{
+				"contains": {"c"},
+				"lacks":    {"s"},
+				"regex":    {"a"},
+				"wildcard": {"?r*"},
+				"unique":   {"true"},
+				"vowels":   {"2"},
+				"prefix":   {"c"},
+				"suffix":   {"e"},
+			},
+			result: qordle.Dictionary{"crane", "crate"},
+		},
+		{
+			name:   "no vowels",
+			values: url.Values{"vowels": {"0"}},
+			result: qordle.Dictionary{"nymph"},
+		},
+		{
+			name:   "invalid vowels",
+			values: url.Values{"vowels": {"two"}},
+			err:    "invalid vowels `two`",
+		},
+		{
+			name:   "invalid unique",
+			values: url.Values{"unique": {"maybe"}},
+			err:    "invalid unique `maybe`",
+		},
+		{
+			name:   "invalid regex",
+			values: url.Values{"regex": {"["}},
+			err:    "invalid regex `[`: error parsing regexp: missing closing ]: `[`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			q, err := qordle.NewQuery(tt.values)
+			if err == nil {
+				var fns []qordle.FilterFunc
+				fns, err = q.Filters()
+				if err == nil {
+					a.Equal(tt.result, qordle.Filter(words, fns...))
+				}
+			}
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package qordle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constructor creates a new instance of a Strategy
+type Constructor func() Strategy
+
+// registration describes a registered strategy
+type registration struct {
+	description string
+	constructor Constructor
+}
+
+// Registry manages the available strategies by name
+type Registry struct {
+	trie Trie[*registration]
+}
+
+// NewRegistry creates a new empty registry
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+// DefaultRegistry creates a new registry with all built-in strategies
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, r := range []struct {
+		description string
+		constructor Constructor
+	}{
+		{
+			"Sort the word list alphabetically",
+			func() Strategy { return new(Alpha) },
+		},
+		{
+			"Rank words by bigram frequency of their letters",
+			func() Strategy { return new(Bigram) },
+		},
+		{
+			"Rank words by how many candidates each guess eliminates",
+			func() Strategy { return new(Elimination) },
+		},
+		{
+			"Rank words by the frequency of their letters in the remaining list",
+			func() Strategy { return new(Frequency) },
+		},
+		{
+			"Rank words by the frequency of their letters at positions not yet known",
+			func() Strategy { return new(InformedFrequency) },
+		},
+		{
+			"Rank words by how often each letter appears in a position not yet known",
+			func() Strategy { return new(InformedPosition) },
+		},
+		{
+			"Rank words by their likelihood as a real word under an n-gram model of a word list",
+			func() Strategy { return new(NGram) },
+		},
+		{
+			"Rank words by how often each letter appears in its position",
+			func() Strategy { return new(Position) },
+		},
+	} {
+		// the built-in strategies have unique names so no error is possible
+		_ = registry.Register(r.constructor().String(), r.description, r.constructor)
+	}
+	return registry
+}
+
+// Register the strategy constructor by name
+func (r *Registry) Register(name, description string, constructor Constructor) error {
+	name = strings.ToLower(name)
+	if name == "" {
+		return errors.New("missing strategy name")
+	}
+	if r.trie.Node(name).Word() {
+		return fmt.Errorf("duplicate strategy `%s`", name)
+	}
+	r.trie.Add(name, &registration{description: description, constructor: constructor})
+	return nil
+}
+
+// Strategy returns a new instance of the strategy uniquely identified by the prefix
+func (r *Registry) Strategy(prefix string) (Strategy, error) {
+	node := r.trie.Node(strings.ToLower(prefix))
+	switch {
+	case node == nil:
+		return nil, fmt.Errorf("unknown strategy `%s`", prefix)
+	case node.Word():
+		return node.value.constructor(), nil
+	}
+	names := node.Strings()
+	if len(names) != 1 {
+		for i := range names {
+			names[i] = prefix + names[i]
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("ambiguous strategy `%s` matches %s", prefix, strings.Join(names, ", "))
+	}
+	return node.Value(names[0]).constructor(), nil
+}
+
+// Strategies returns the sorted names of all registered strategies
+func (r *Registry) Strategies() []string {
+	names := r.trie.Strings()
+	sort.Strings(names)
+	return names
+}
+
+// Descriptions returns the description of each registered strategy by name
+func (r *Registry) Descriptions() map[string]string {
+	names := r.trie.Strings()
+	descriptions := make(map[string]string, len(names))
+	for _, name := range names {
+		descriptions[name] = r.trie.Node(name).value.description
+	}
+	return descriptions
+}
@@ -0,0 +1,102 @@
+package qordle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, prefix, strategy, err string
+	}{
+		{
+			name:     "exact name",
+			prefix:   "frequency",
+			strategy: "frequency",
+		},
+		{
+			name:     "unique prefix",
+			prefix:   "el",
+			strategy: "elimination",
+		},
+		{
+			name:     "case insensitive",
+			prefix:   "Pos",
+			strategy: "position",
+		},
+		{
+			name:     "unique prefix with a shared stem",
+			prefix:   "informed-p",
+			strategy: "informed-position",
+		},
+		{
+			name:   "ambiguous prefix",
+			prefix: "inf",
+			err:    "ambiguous strategy `inf` matches informed-frequency, informed-position",
+		},
+		{
+			name:   "unknown",
+			prefix: "foobar",
+			err:    "unknown strategy `foobar`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			strategy, err := qordle.DefaultRegistry().Strategy(tt.prefix)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				a.Nil(strategy)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.strategy, strategy.String())
+		})
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	registry := qordle.NewRegistry()
+	a.Empty(registry.Strategies())
+
+	identity := func() qordle.Strategy { return new(identity) }
+	a.NoError(registry.Register("identity", "return the words unchanged", identity))
+	a.NoError(registry.Register("Ident", "a prefix of identity", identity))
+	a.EqualError(registry.Register("identity", "again", identity), "duplicate strategy `identity`")
+	a.EqualError(registry.Register("", "no name", identity), "missing strategy name")
+
+	a.Equal([]string{"ident", "identity"}, registry.Strategies())
+	a.Equal(map[string]string{
+		"ident":    "a prefix of identity",
+		"identity": "return the words unchanged",
+	}, registry.Descriptions())
+
+	// an exact match is preferred over a longer name sharing the prefix
+	strategy, err := registry.Strategy("ident")
+	a.NoError(err)
+	a.Equal("identity", strategy.String())
+	_, err = registry.Strategy("id")
+	a.EqualError(err, "ambiguous strategy `id` matches ident, identity")
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	registry := qordle.DefaultRegistry()
+	names := registry.Strategies()
+	descriptions := registry.Descriptions()
+	a.Len(descriptions, len(names))
+	for _, name := range names {
+		a.NotEmpty(descriptions[name])
+		strategy, err := registry.Strategy(name)
+		a.NoError(err)
+		a.Equal(name, strategy.String())
+	}
+}
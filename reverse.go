@@ -0,0 +1,293 @@
+package qordle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Grid is the marks of each row of a shared game without the letters
+type Grid []Marks
+
+// shared maps the emoji, including the high contrast colors, and letters of a shared grid to marks
+var shared = map[rune]Mark{
+	'🟩': MarkExact,
+	'🟧': MarkExact,
+	'G': MarkExact,
+	'🟨': MarkMisplaced,
+	'🟦': MarkMisplaced,
+	'Y': MarkMisplaced,
+	'⬛': MarkMiss,
+	'⬜': MarkMiss,
+	'B': MarkMiss,
+	'X': MarkMiss,
+	'-': MarkMiss,
+}
+
+// header matches the first line of a shared game with its score, eg `Wordle 1,234 3/6*`
+var header = regexp.MustCompile(`(^|\s)[\dX]/\d+\*?(\s|$)`)
+
+// ParseGrid parses each row of tiles, eg `⬛🟨⬛⬛🟩` or `BYBBG`, into marks, skipping the header
+// of a pasted share
+func ParseGrid(rows ...string) (Grid, error) {
+	grid := make(Grid, 0, len(rows))
+	for _, row := range rows {
+		if header.MatchString(row) {
+			continue
+		}
+		var marks Marks
+		for _, r := range row {
+			// variation selectors follow some emoji
+			if unicode.IsSpace(r) || unicode.Is(unicode.Variation_Selector, r) {
+				continue
+			}
+			mark, ok := shared[unicode.ToUpper(r)]
+			if !ok {
+				return nil, fmt.Errorf("invalid grid row `%s`", row)
+			}
+			marks = append(marks, mark)
+		}
+		if len(marks) > 0 {
+			grid = append(grid, marks)
+		}
+	}
+	return grid, nil
+}
+
+// emoji returns the marks as a row of a shared grid
+func emoji(marks Marks) string {
+	var buf strings.Builder
+	for _, mark := range marks {
+		switch mark {
+		case MarkExact:
+			buf.WriteRune('🟩')
+		case MarkMisplaced:
+			buf.WriteRune('🟨')
+		default:
+			buf.WriteRune('⬛')
+		}
+	}
+	return buf.String()
+}
+
+// hard returns a filter for words playable in hard mode after the guess with the marks,
+// where exact letters stay in place and misplaced letters are used again
+func hard(guess string, marks Marks) FilterFunc {
+	required := make(map[byte]int)
+	for i := range marks {
+		if marks[i] != MarkMiss {
+			required[guess[i]]++
+		}
+	}
+	return func(word string) bool {
+		for i := range marks {
+			if marks[i] == MarkExact && word[i] != guess[i] {
+				return false
+			}
+		}
+		for letter, n := range required {
+			if strings.Count(word, string(letter)) < n {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// playable returns true if the word passes every hard mode filter
+func playable(word string, fns []FilterFunc) bool {
+	for _, fn := range fns {
+		if !fn(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reversed is the words producing the marks of a row of the grid
+type Reversed struct {
+	Pattern    string     `json:"pattern"`
+	Candidates int        `json:"candidates"`
+	Words      Dictionary `json:"words"`
+}
+
+// Sequence is the words played for every row of the grid
+type Sequence struct {
+	Rank  int      `json:"rank"`
+	Score float64  `json:"score"`
+	Words []string `json:"words"`
+}
+
+// Reversal is the words which could have been played for a grid shared for the secret
+type Reversal struct {
+	Secret    string      `json:"secret"`
+	Strategy  string      `json:"strategy"`
+	Hard      bool        `json:"hard"`
+	Patterns  []*Reversed `json:"patterns"`
+	Sequences []*Sequence `json:"sequences"`
+}
+
+func (r *Reversal) Header() []string {
+	return []string{"rank", "score", "words"}
+}
+
+func (r *Reversal) Rows() [][]string {
+	rows := make([][]string, len(r.Sequences))
+	for i, s := range r.Sequences {
+		rows[i] = []string{
+			strconv.Itoa(s.Rank),
+			strconv.FormatFloat(s.Score, 'f', 4, 64),
+			strings.Join(s.Words, " "),
+		}
+	}
+	return rows
+}
+
+// Reverse finds the words of the dictionary producing each row of the grid for the secret and
+// the `limit` most plausible sequences of words, scoring each word by its rank under the strategy
+// among the words of its row
+func Reverse(
+	ctx context.Context, strategy Strategy, dictionary Dictionary,
+	secret string, grid Grid, hardMode bool, limit int,
+) (*Reversal, error) {
+	switch {
+	case len(grid) == 0:
+		return nil, errors.New("missing grid")
+	case limit <= 0:
+		return nil, errors.New("limit must be positive")
+	}
+	secret = strings.ToLower(secret)
+	for i := range grid {
+		if len(grid[i]) != len(secret) {
+			return nil, fmt.Errorf("row %d: %w", i+1, ErrInvalidLength)
+		}
+	}
+	ranked, err := ApplyContext(ctx, strategy, Filter(dictionary, Length(len(secret)), IsLower()), nil)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := Check(secret, ranked...)
+	if err != nil {
+		return nil, err
+	}
+	reversal := &Reversal{
+		Secret:   secret,
+		Strategy: strategy.String(),
+		Hard:     hardMode,
+		Patterns: make([]*Reversed, len(grid)),
+	}
+	for i := range grid {
+		row := &Reversed{Pattern: emoji(grid[i]), Words: Dictionary{}}
+		for j := range ranked {
+			if slices.Equal(checks[j], grid[i]) {
+				row.Words = append(row.Words, ranked[j])
+			}
+		}
+		row.Candidates = len(row.Words)
+		reversal.Patterns[i] = row
+	}
+
+	// a beam search keeping the best `limit` sequences after each row which, without hard mode,
+	// finds the best sequences since the score of each word is independent of the other rows
+	beam := []*Sequence{{Words: []string{}}}
+	for _, row := range reversal.Patterns {
+		var next []*Sequence
+		for _, s := range beam {
+			var fns []FilterFunc
+			if hardMode {
+				for k := range s.Words {
+					fns = append(fns, hard(s.Words[k], grid[k]))
+				}
+			}
+			var extended int
+			for j := 0; extended < limit && j < len(row.Words); j++ {
+				word := row.Words[j]
+				if !playable(word, fns) {
+					continue
+				}
+				extended++
+				next = append(next, &Sequence{
+					Score: s.Score + float64(len(row.Words)-j)/float64(len(row.Words)),
+					Words: append(slices.Clip(s.Words), word),
+				})
+			}
+		}
+		sort.SliceStable(next, func(x, y int) bool {
+			if next[x].Score != next[y].Score {
+				return next[x].Score > next[y].Score
+			}
+			return strings.Join(next[x].Words, " ") < strings.Join(next[y].Words, " ")
+		})
+		beam = next[:min(limit, len(next))]
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	for i, s := range beam {
+		s.Rank, s.Score = i+1, s.Score/float64(len(grid))
+	}
+	reversal.Sequences = beam
+	return reversal, nil
+}
+
+func CommandReverse() *cli.Command {
+	return &cli.Command{
+		Name:      "reverse",
+		Category:  categoryWordle,
+		Usage:     "Find the words which could have been played for a shared grid",
+		ArgsUsage: "<secret> [<row>...]",
+		Description: "Each row of the grid is a line of tiles such as ⬛🟨⬛⬛🟩, or the letters G, Y " +
+			"and B, read from the arguments or else from stdin. For each row every word producing the " +
+			"marks against the secret is listed along with the most plausible sequences of words, " +
+			"scoring each word by its rank under the strategy among the words of its row. With --hard " +
+			"each sequence keeps the exact letters in place and reuses the misplaced letters.",
+		Flags: slices.Concat(
+			[]cli.Flag{
+				&cli.BoolFlag{
+					Name:  "hard",
+					Usage: "only consider sequences of words allowed in hard mode",
+				},
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "number of sequences to rank",
+					Value: 10,
+				},
+			},
+			wordlistFlags(), strategyFlags(), ngramFlags(),
+		),
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return errors.New("expected a secret")
+			}
+			secret, rows := c.Args().First(), c.Args().Tail()
+			if len(rows) == 0 {
+				var err error
+				rows, err = read(c.App.Reader)
+				if err != nil {
+					return err
+				}
+			}
+			grid, err := ParseGrid(rows...)
+			if err != nil {
+				return err
+			}
+			dictionary, strategy, err := prepare(c, "possible", "solutions")
+			if err != nil {
+				return err
+			}
+			reversal, err := Reverse(c.Context, strategy, dictionary, secret, grid, c.Bool("hard"), c.Int("limit"))
+			if err != nil {
+				return err
+			}
+			return Runtime(c).Encoder.Encode(reversal)
+		},
+	}
+}
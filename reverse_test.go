@@ -0,0 +1,213 @@
+package qordle_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestParseGrid(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name string
+		rows []string
+		grid qordle.Grid
+		err  string
+	}{
+		{
+			name: "emoji",
+			rows: []string{"⬛🟨⬛⬛🟩", "🟩🟩🟩🟩🟩"},
+			grid: qordle.Grid{
+				{qordle.MarkMiss, qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkExact},
+				{qordle.MarkExact, qordle.MarkExact, qordle.MarkExact, qordle.MarkExact, qordle.MarkExact},
+			},
+		},
+		{
+			name: "light mode and high contrast",
+			rows: []string{"⬜🟦⬜⬜🟧"},
+			grid: qordle.Grid{
+				{qordle.MarkMiss, qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkExact},
+			},
+		},
+		{
+			name: "letters",
+			rows: []string{"bYbxG", "gg-gg"},
+			grid: qordle.Grid{
+				{qordle.MarkMiss, qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkExact},
+				{qordle.MarkExact, qordle.MarkExact, qordle.MarkMiss, qordle.MarkExact, qordle.MarkExact},
+			},
+		},
+		{
+			name: "blank rows and variation selectors",
+			rows: []string{"", " ⬛️🟨⬛️⬛️🟩 ", "  "},
+			grid: qordle.Grid{
+				{qordle.MarkMiss, qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkExact},
+			},
+		},
+		{
+			name: "pasted share",
+			rows: []string{"Wordle 1,234 3/6*", "", "⬛🟨⬛⬛🟩", "🟨🟩⬛⬛⬛", "🟩🟩🟩🟩🟩"},
+			grid: qordle.Grid{
+				{qordle.MarkMiss, qordle.MarkMisplaced, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkExact},
+				{qordle.MarkMisplaced, qordle.MarkExact, qordle.MarkMiss, qordle.MarkMiss, qordle.MarkMiss},
+				{qordle.MarkExact, qordle.MarkExact, qordle.MarkExact, qordle.MarkExact, qordle.MarkExact},
+			},
+		},
+		{
+			name: "lost share",
+			rows: []string{"Wordle 1.234 X/6", "GGBGG"},
+			grid: qordle.Grid{
+				{qordle.MarkExact, qordle.MarkExact, qordle.MarkMiss, qordle.MarkExact, qordle.MarkExact},
+			},
+		},
+		{
+			name: "invalid tile",
+			rows: []string{"⬛🟨⬛⬛🟥"},
+			err:  "invalid grid row `⬛🟨⬛⬛🟥`",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			grid, err := qordle.ParseGrid(tt.rows...)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.grid, grid)
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dt, err := qordle.Read("solutions")
+	a.NoError(err)
+	grid, err := qordle.ParseGrid("⬛⬛🟨⬛🟨", "🟨🟩⬛⬛⬛", "🟩🟩🟩🟩🟩")
+	a.NoError(err)
+
+	reversal, err := qordle.Reverse(context.Background(), new(qordle.Frequency), dt, "table", grid, false, 5)
+	a.NoError(err)
+	a.Equal("table", reversal.Secret)
+	a.Len(reversal.Patterns, 3)
+	a.Equal("🟨🟩⬛⬛⬛", reversal.Patterns[1].Pattern)
+	a.Equal(len(reversal.Patterns[1].Words), reversal.Patterns[1].Candidates)
+	a.Equal(qordle.Dictionary{"table"}, reversal.Patterns[2].Words)
+	for i, p := range reversal.Patterns {
+		for _, word := range p.Words {
+			checks, err := qordle.Check("table", word)
+			a.NoError(err)
+			a.Equal(grid[i], checks[0])
+		}
+	}
+	a.Len(reversal.Sequences, 5)
+	a.Equal(1, reversal.Sequences[0].Rank)
+	a.Equal([]string{"opera", "baron", "table"}, reversal.Sequences[0].Words)
+	a.Equal(1.0, reversal.Sequences[0].Score)
+	for i := 1; i < len(reversal.Sequences); i++ {
+		a.GreaterOrEqual(reversal.Sequences[i-1].Score, reversal.Sequences[i].Score)
+	}
+
+	// in hard mode the misplaced `e` of opera must be played again
+	reversal, err = qordle.Reverse(context.Background(), new(qordle.Frequency), dt, "table", grid, true, 5)
+	a.NoError(err)
+	a.Equal([]string{"opera", "eager", "table"}, reversal.Sequences[0].Words)
+	for _, s := range reversal.Sequences {
+		a.NotEqual("baron", s.Words[1])
+	}
+
+	_, err = qordle.Reverse(context.Background(), new(qordle.Frequency), dt, "table", nil, false, 5)
+	a.EqualError(err, "missing grid")
+	_, err = qordle.Reverse(context.Background(), new(qordle.Frequency), dt, "table", grid, false, 0)
+	a.EqualError(err, "limit must be positive")
+	_, err = qordle.Reverse(context.Background(), new(qordle.Frequency), dt, "tables", grid, false, 5)
+	a.ErrorIs(err, qordle.ErrInvalidLength)
+}
+
+func TestReverseCommand(t *testing.T) {
+	a := assert.New(t)
+	decode := func(c *cli.Context) *qordle.Reversal {
+		var res qordle.Reversal
+		a.NoError(json.NewDecoder(c.App.Writer.(io.Reader)).Decode(&res))
+		return &res
+	}
+	for _, tt := range []harness{
+		{
+			name: "reverse",
+			args: []string{"reverse", "-w", "solutions", "--limit", "2", "table", "🟨⬛⬛⬛⬛", "🟩🟩🟩🟩🟩"},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.Equal("frequency", res.Strategy)
+				a.False(res.Hard)
+				a.Equal(91, res.Patterns[0].Candidates)
+				a.Len(res.Sequences, 2)
+				a.Equal([]string{"arson", "table"}, res.Sequences[0].Words)
+				a.Equal([]string{"acorn", "table"}, res.Sequences[1].Words)
+				return nil
+			},
+		},
+		{
+			name: "read the grid from stdin",
+			args: []string{"reverse", "-w", "solutions", "--hard", "table"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader("BBYBY\nYGBBB\nGGGGG\n")
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.True(res.Hard)
+				a.Equal([]string{"opera", "eager", "table"}, res.Sequences[0].Words)
+				return nil
+			},
+		},
+		{
+			name: "read a pasted share from stdin",
+			args: []string{"reverse", "-w", "solutions", "--hard", "table"},
+			before: func(c *cli.Context) error {
+				c.App.Reader = strings.NewReader("Wordle 1,234 3/6*\n\n⬛⬛🟨⬛🟨\n🟨🟩⬛⬛⬛\n🟩🟩🟩🟩🟩\n")
+				return nil
+			},
+			after: func(c *cli.Context) error {
+				res := decode(c)
+				a.True(res.Hard)
+				a.Equal([]string{"opera", "eager", "table"}, res.Sequences[0].Words)
+				return nil
+			},
+		},
+		{
+			name: "missing secret",
+			args: []string{"reverse"},
+			err:  "expected a secret",
+		},
+		{
+			name: "invalid grid",
+			args: []string{"reverse", "table", "BBYBR"},
+			err:  "invalid grid row `BBYBR`",
+		},
+		{
+			name: "invalid strategy",
+			args: []string{"reverse", "-s", "foobar", "table", "GGGGG"},
+			err:  "unknown strategy `foobar`",
+		},
+		{
+			name: "invalid length",
+			args: []string{"reverse", "table", "GGGG"},
+			err:  qordle.ErrInvalidLength.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt, qordle.CommandReverse)
+		})
+	}
+}
@@ -43,27 +43,46 @@ func prepare(c *cli.Context, wordlist ...string) (Dictionary, Strategy, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	var strategy Strategy
-	strategies := c.StringSlice("strategy")
-	switch n := len(strategies); n {
-	case 1:
-		strategy, err = Runtime(c).Strategies.Strategy(strategies[0])
-		if err != nil {
-			return nil, nil, err
-		}
-	default:
-		s := make([]Strategy, n)
-		for i := range strategies {
-			strategy, err = Runtime(c).Strategies.Strategy(strategies[i])
-			if err != nil {
-				return nil, nil, err
-			}
-			s[i] = strategy
-		}
-		strategy = NewChain(s...)
+	strategy, err := build(Runtime(c).Strategies, c.StringSlice("strategy"))
+	if err != nil {
+		return nil, nil, err
 	}
+	SetWorkers(strategy, c.Int("workers"))
+	SetTables(strategy, lettered(c, dictionary))
+	model, err := modeled(c, dictionary)
+	if err != nil {
+		return nil, nil, err
+	}
+	SetModel(strategy, model)
 	if c.Bool("speculate") {
 		strategy = NewSpeculator(dictionary, strategy)
 	}
 	return dictionary, strategy, nil
 }
+
+// lettered returns the tables computed from the dictionary if a word list was specified
+// or else the generated tables
+func lettered(c *cli.Context, dictionary Dictionary) *Tables {
+	for _, name := range []string{"wordlist", "Wordlist", "intersect"} {
+		if c.IsSet(name) {
+			return tablesFor(dictionary)
+		}
+	}
+	return DefaultTables()
+}
+
+// build the strategy from the names, chaining them if more than one
+func build(strategies Strategies, names []string) (Strategy, error) {
+	if len(names) == 1 {
+		return strategies.Strategy(names[0])
+	}
+	s := make([]Strategy, len(names))
+	for i := range names {
+		strategy, err := strategies.Strategy(names[i])
+		if err != nil {
+			return nil, err
+		}
+		s[i] = strategy
+	}
+	return NewChain(s...), nil
+}
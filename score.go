@@ -1,7 +1,9 @@
 package qordle
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"strings"
 	"unicode"
 
@@ -17,10 +19,14 @@ type Marks []Mark
 
 const (
 	yellow = '.'
+	// unknown precedes an uppercase letter since a symbol before a lowercase letter marks it misplaced
+	unknown = '?'
 
 	MarkMiss      Mark = 0
 	MarkMisplaced Mark = 1
 	MarkExact     Mark = 2
+	// MarkUnknown is a letter guessed at the position with a forgotten mark, only found in patterns
+	MarkUnknown Mark = 3
 )
 
 var ErrInvalidLength = errors.New("secret and guess lengths do not match")
@@ -70,23 +76,51 @@ func Score(secret string, guesses ...string) ([]string, error) {
 	}
 	scores := make([]string, len(checks))
 	for i := range checks {
-		var pattern []rune
-		score, guess := checks[i], []rune(guesses[i])
-		for j := range score {
-			switch score[j] {
-			case MarkExact:
-				pattern = append(pattern, unicode.ToUpper(guess[j]))
-			case MarkMiss:
-				pattern = append(pattern, unicode.ToLower(guess[j]))
-			case MarkMisplaced:
-				pattern = append(pattern, yellow, unicode.ToLower(guess[j]))
-			}
-		}
-		scores[i] = string(pattern)
+		scores[i] = marked(guesses[i], checks[i])
 	}
 	return scores, nil
 }
 
+// marked returns the feedback pattern of the guess with the marks
+func marked(guess string, marks Marks) string {
+	var pattern []rune
+	letters := []rune(guess)
+	for j := range marks {
+		switch marks[j] {
+		case MarkExact:
+			pattern = append(pattern, unicode.ToUpper(letters[j]))
+		case MarkMiss:
+			pattern = append(pattern, unicode.ToLower(letters[j]))
+		case MarkMisplaced:
+			pattern = append(pattern, yellow, unicode.ToLower(letters[j]))
+		}
+	}
+	return string(pattern)
+}
+
+// scored are the scores of the guesses encoded as the list of scores
+type scored struct {
+	secret  string
+	guesses []string
+	scores  []string
+}
+
+func (s *scored) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.scores)
+}
+
+func (s *scored) Header() []string {
+	return nil
+}
+
+func (s *scored) Rows() [][]string {
+	return [][]string{s.scores}
+}
+
+func (s *scored) Render(w io.Writer, tiles *Tiles) error {
+	return tiles.Render(w, s.secret, s.guesses...)
+}
+
 func CommandScore() *cli.Command {
 	return &cli.Command{
 		Name:      "score",
@@ -94,11 +128,12 @@ func CommandScore() *cli.Command {
 		Usage:     "Score the guesses against the secret",
 		ArgsUsage: "<secret> <guess> [, <guess>]",
 		Action: func(c *cli.Context) error {
-			scores, err := Score(c.Args().First(), c.Args().Tail()...)
+			secret, guesses := c.Args().First(), c.Args().Tail()
+			scores, err := Score(secret, guesses...)
 			if err != nil {
 				return err
 			}
-			return Runtime(c).Encoder.Encode(scores)
+			return Runtime(c).Encoder.Encode(&scored{secret: secret, guesses: guesses, scores: scores})
 		},
 	}
 }
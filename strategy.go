@@ -1,7 +1,9 @@
 package qordle
 
 import (
+	"context"
 	"fmt"
+	sys "runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -24,6 +26,11 @@ func strategyFlags() []cli.Flag {
 			Usage:   "speculate if necessary",
 			Value:   false,
 		},
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "number of workers used by each strategy supporting concurrency",
+			Value: sys.NumCPU(),
+		},
 	}
 }
 
@@ -33,9 +40,7 @@ func CommandStrategies() *cli.Command {
 		Category: categoryWordle,
 		Usage:    "List all available strategies",
 		Action: func(c *cli.Context) error {
-			strategies := Runtime(c).Strategies.Strategies()
-			sort.Strings(strategies)
-			return Runtime(c).Encoder.Encode(strategies)
+			return Runtime(c).Encoder.Encode(Runtime(c).Strategies.Descriptions())
 		},
 	}
 }
@@ -45,9 +50,66 @@ type Strategy interface {
 	Apply(Dictionary) Dictionary
 }
 
+// KnowledgeStrategy is a Strategy which also uses the state of the game
+type KnowledgeStrategy interface {
+	Strategy
+	ApplyKnowledge(Dictionary, *Knowledge) Dictionary
+}
+
+// ApplyKnowledge applies the strategy with the knowledge if supported by the strategy
+func ApplyKnowledge(strategy Strategy, words Dictionary, knowledge *Knowledge) Dictionary {
+	if s, ok := strategy.(KnowledgeStrategy); ok {
+		return s.ApplyKnowledge(words, knowledge)
+	}
+	return strategy.Apply(words)
+}
+
+// ContextStrategy is a Strategy which stops applying when the context is done
+type ContextStrategy interface {
+	Strategy
+	ApplyContext(context.Context, Dictionary, *Knowledge) (Dictionary, error)
+}
+
+// ApplyContext applies the strategy with the knowledge, stopping early if the strategy
+// supports cancellation and the context is done
+func ApplyContext(ctx context.Context, strategy Strategy, words Dictionary, knowledge *Knowledge) (Dictionary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s, ok := strategy.(ContextStrategy); ok {
+		return s.ApplyContext(ctx, words, knowledge)
+	}
+	return ApplyKnowledge(strategy, words, knowledge), nil
+}
+
+// Pool is implemented by strategies which distribute their work across a pool of workers
+type Pool interface {
+	Strategy
+	Workers(int)
+}
+
+// SetWorkers sets the number of workers for the strategy if supported by the strategy
+func SetWorkers(strategy Strategy, n int) {
+	if s, ok := strategy.(Pool); ok {
+		s.Workers(n)
+	}
+}
+
+// workers returns the number of workers to use for `n` units of work
+func workers(pool, n int) int {
+	if pool <= 0 {
+		pool = sys.NumCPU()
+	}
+	return max(1, min(pool, n))
+}
+
 type Strategies interface {
+	// Strategy returns the strategy uniquely identified by the prefix
 	Strategy(string) (Strategy, error)
+	// Strategies returns the sorted names of all strategies
 	Strategies() []string
+	// Descriptions returns the description of each strategy by name
+	Descriptions() map[string]string
 }
 
 // Alpha orders the dictionary alphabetically
@@ -83,7 +145,7 @@ func mkdict(scores map[int][]string) Dictionary {
 	return dict
 }
 
-func mkdictf(scores map[string]float64, less func(i, j float64) bool) Dictionary {
+func mkdictf(scores map[string]float64, freqs map[rune]float64, less func(i, j float64) bool) Dictionary {
 	type tuple struct {
 		word string
 		rank float64
@@ -99,10 +161,10 @@ func mkdictf(scores map[string]float64, less func(i, j float64) bool) Dictionary
 			// start with frequency ordering
 			yi, yj := 0.0, 0.0
 			for _, v := range tuples[i].word {
-				yi += frequencies[v]
+				yi += freqs[v]
 			}
 			for _, v := range tuples[j].word {
-				yj += frequencies[v]
+				yj += freqs[v]
 			}
 			// revert to alphabetical ordering if necessary
 			if yi == yj {
@@ -187,51 +249,153 @@ func (s *Frequency) Apply(words Dictionary) Dictionary {
 	return mkdict(scores)
 }
 
+// InformedPosition sorts the word list by letter position skipping resolved positions
+type InformedPosition struct{}
+
+func (s *InformedPosition) String() string {
+	return "informed-position"
+}
+
+func (s *InformedPosition) Apply(words Dictionary) Dictionary {
+	return s.ApplyKnowledge(words, nil)
+}
+
+func (s *InformedPosition) ApplyKnowledge(words Dictionary, knowledge *Knowledge) Dictionary {
+	// count the number of times a letter appears at an unresolved position
+	pos := make(map[rune]map[int]int)
+	for _, word := range words {
+		for index, letter := range []rune(word) {
+			if knowledge.Resolved(index) || knowledge.Absent(letter) {
+				continue
+			}
+			if _, ok := pos[letter]; !ok {
+				pos[letter] = make(map[int]int)
+			}
+			pos[letter][index]++
+		}
+	}
+
+	// score the word by summing the position count for each unresolved letter
+	scores := make(map[int][]string)
+	for _, word := range words {
+		s := 0
+		for index, letter := range []rune(word) {
+			s += pos[letter][index]
+		}
+		scores[s] = append(scores[s], word)
+	}
+
+	return mkdict(scores)
+}
+
+// InformedFrequency sorts the word list by the frequency of letters at unresolved positions
+type InformedFrequency struct{}
+
+func (s *InformedFrequency) String() string {
+	return "informed-frequency"
+}
+
+func (s *InformedFrequency) Apply(words Dictionary) Dictionary {
+	return s.ApplyKnowledge(words, nil)
+}
+
+func (s *InformedFrequency) ApplyKnowledge(words Dictionary, knowledge *Knowledge) Dictionary {
+	// find the most common letters at the unresolved positions of the word list
+	freq := make(map[rune]int)
+	for i := range words {
+		word := []rune(words[i])
+		for j := range word {
+			if knowledge.Resolved(j) || knowledge.Absent(word[j]) {
+				continue
+			}
+			freq[word[j]]++
+		}
+	}
+
+	// map each word to its sum of unresolved letters (skip duplicates)
+	scores := make(map[int][]string)
+	for i, word := range words {
+		n := 0
+		word := []rune(word)
+		s := make(map[rune]struct{}, len(word))
+		for j := range word {
+			if knowledge.Resolved(j) {
+				continue
+			}
+			if _, ok := s[word[j]]; !ok {
+				s[word[j]] = struct{}{}
+				n += freq[word[j]]
+			}
+		}
+		scores[n] = append(scores[n], words[i])
+	}
+
+	return mkdict(scores)
+}
+
 // Bigram sorts the dictionary by the bigram frequency of the word
-type Bigram struct{}
+type Bigram struct {
+	tables *Tables
+}
 
 func (s *Bigram) String() string {
 	return "bigram"
 }
 
+// Tables sets the letter tables, defaulting to the generated tables
+func (s *Bigram) Tables(tables *Tables) {
+	s.tables = tables
+}
+
 func (s *Bigram) Apply(words Dictionary) Dictionary {
-	var i int
-	var val float64
+	tables := s.tables.orDefault()
 	res := make(map[string]float64, len(words))
 	for _, word := range words {
-		switch n := len(word); n {
-		case 0, 1:
-		default:
-			i, val = 0, 0.0
-			for i+2 <= n {
-				val += bigrams[word[i:i+2]]
-				i++
-			}
-			res[word] = val
+		grams := pairs(word)
+		if len(grams) == 0 {
+			continue
+		}
+		var val float64
+		for _, gram := range grams {
+			val += tables.Bigrams[gram]
 		}
+		res[word] = val
 	}
 	if len(res) == 0 {
 		return words
 	}
-	return mkdictf(res, func(i, j float64) bool {
+	return mkdictf(res, tables.Frequencies, func(i, j float64) bool {
 		return i > j
 	})
 }
 
-type Elimination struct{}
+// Elimination sorts the dictionary by how much each word eliminates when used as the secret
+type Elimination struct {
+	workers int
+	tables  *Tables
+}
 
 func (s *Elimination) String() string {
 	return "elimination"
 }
 
-func (s *Elimination) score(words Dictionary, i int) map[string]float64 {
-	secret := words[i]
-	marks, err := Check(secret, words...)
+// Workers sets the number of concurrent workers, defaulting to the number of cpus
+func (s *Elimination) Workers(n int) {
+	s.workers = n
+}
+
+// Tables sets the letter tables, defaulting to the generated tables
+func (s *Elimination) Tables(tables *Tables) {
+	s.tables = tables
+}
+
+func (s *Elimination) score(words Dictionary, i int, scores map[string]float64) error {
+	positions := s.tables.orDefault().Positions
+	secret := []rune(words[i])
+	marks, err := Check(words[i], words...)
 	if err != nil {
-		log.Error().Err(err).Str("secret", secret).Msg("elimination")
-		return nil
+		return err
 	}
-	scores := make(map[string]float64)
 	for j := range marks {
 		if i != j {
 			// skip the identity
@@ -241,54 +405,81 @@ func (s *Elimination) score(words Dictionary, i int) map[string]float64 {
 				case MarkMiss:
 					// no score
 				case MarkMisplaced:
-					score += positions[rune(secret[k])][k]
+					score += positions[secret[k]][k]
 				case MarkExact:
-					score += (2 * positions[rune(secret[k])][k])
+					score += (2 * positions[secret[k]][k])
 				}
 			}
-			scores[words[j]] = score
+			scores[words[j]] += score
 		}
 	}
-	return scores
+	return nil
 }
 
 func (s *Elimination) Apply(words Dictionary) Dictionary {
+	dict, err := s.ApplyContext(context.Background(), words, nil)
+	if err != nil {
+		log.Error().Err(err).Msg(s.String())
+		return nil
+	}
+	return dict
+}
+
+func (s *Elimination) ApplyContext(ctx context.Context, words Dictionary, _ *Knowledge) (Dictionary, error) {
 	switch len(words) {
 	case 0, 1:
-		return words
+		return words, nil
 	}
 
-	var wg sync.WaitGroup
-	scoresc := make(chan map[string]float64, len(words)/2)
-	for i := range words {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			scoresc <- s.score(words, i)
-		}(i)
-	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	indices := make(chan int)
 	go func() {
-		defer close(scoresc)
-		wg.Wait()
+		defer close(indices)
+		for i := range words {
+			select {
+			case <-ctx.Done():
+				return
+			case indices <- i:
+			}
+		}
 	}()
 
-	res := make(map[string]float64)
-	for scores := range scoresc {
-		if scores == nil {
-			return nil
-		}
-		for key, val := range scores {
+	var wg sync.WaitGroup
+	scores := make([]map[string]float64, workers(s.workers, len(words)))
+	for w := range scores {
+		wg.Go(func() {
+			res := make(map[string]float64, len(words))
+			for i := range indices {
+				if err := s.score(words, i, res); err != nil {
+					cancel(err)
+					return
+				}
+			}
+			scores[w] = res
+		})
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]float64, len(words))
+	for i := range scores {
+		for key, val := range scores[i] {
 			res[key] += val
 		}
 	}
-	return mkdictf(res, func(i, j float64) bool {
+	return mkdictf(res, s.tables.orDefault().Frequencies, func(i, j float64) bool {
 		return i > j
-	})
+	}), nil
 }
 
 // Chain chains multiple strategies to sort the wordlist
 type Chain struct {
 	strategies []Strategy
+	tables     *Tables
 }
 
 func (s *Chain) String() string {
@@ -300,37 +491,78 @@ func (s *Chain) String() string {
 }
 
 func (s *Chain) Apply(words Dictionary) Dictionary {
+	return s.ApplyKnowledge(words, nil)
+}
+
+func (s *Chain) ApplyKnowledge(words Dictionary, knowledge *Knowledge) Dictionary {
+	dict, err := s.ApplyContext(context.Background(), words, knowledge)
+	if err != nil {
+		log.Error().Err(err).Msg(s.String())
+		return nil
+	}
+	return dict
+}
+
+func (s *Chain) ApplyContext(ctx context.Context, words Dictionary, knowledge *Knowledge) (Dictionary, error) {
 	switch n := len(s.strategies); n {
 	case 0:
-		return words
+		return words, nil
 	case 1:
-		return s.strategies[0].Apply(words)
+		return ApplyContext(ctx, s.strategies[0], words, knowledge)
 	}
 
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	var wg sync.WaitGroup
-	wordc := make(chan Dictionary, len(s.strategies))
+	results := make([]Dictionary, len(s.strategies))
 	for i := range s.strategies {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			wordc <- s.strategies[i].Apply(words)
-		}(i)
+		wg.Go(func() {
+			dict, err := ApplyContext(ctx, s.strategies[i], words, knowledge)
+			if err != nil {
+				cancel(err)
+				return
+			}
+			results[i] = dict
+		})
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
 	}
-	go func() {
-		defer close(wordc)
-		wg.Wait()
-	}()
 
 	n := float64(len(words))
 	res := make(map[string]float64, len(words))
-	for w := range wordc {
+	for _, w := range results {
 		for i, w := range w {
 			res[w] += float64(i) / n
 		}
 	}
-	return mkdictf(res, func(i, j float64) bool {
+	return mkdictf(res, s.tables.orDefault().Frequencies, func(i, j float64) bool {
 		return i < j
-	})
+	}), nil
+}
+
+// Workers sets the number of workers for each strategy in the chain
+func (s *Chain) Workers(n int) {
+	for i := range s.strategies {
+		SetWorkers(s.strategies[i], n)
+	}
+}
+
+// Tables sets the letter tables for the chain and each strategy in the chain
+func (s *Chain) Tables(tables *Tables) {
+	s.tables = tables
+	for i := range s.strategies {
+		SetTables(s.strategies[i], tables)
+	}
+}
+
+// Model sets the n-gram model for each strategy in the chain
+func (s *Chain) Model(model *NGramModel) {
+	for i := range s.strategies {
+		SetModel(s.strategies[i], model)
+	}
 }
 
 func NewChain(strategies ...Strategy) Strategy {
@@ -419,16 +651,57 @@ func (s *Speculate) with(words Dictionary) Dictionary {
 }
 
 func (s *Speculate) Apply(words Dictionary) Dictionary {
+	return s.ApplyKnowledge(words, nil)
+}
+
+func (s *Speculate) ApplyKnowledge(words Dictionary, knowledge *Knowledge) Dictionary {
+	dict, err := s.ApplyContext(context.Background(), words, knowledge)
+	if err != nil {
+		log.Error().Err(err).Msg(s.String())
+		return nil
+	}
+	return dict
+}
+
+func (s *Speculate) ApplyContext(ctx context.Context, words Dictionary, knowledge *Knowledge) (Dictionary, error) {
 	if len(words) <= s.speculation || s.strategy == nil {
-		return words
+		return words, nil
 	}
 	with := s.with(words)
 	if len(with) == 0 {
-		return s.strategy.Apply(words)
+		return ApplyContext(ctx, s.strategy, words, knowledge)
+	}
+	with, err := ApplyContext(ctx, s.strategy, with, knowledge)
+	if err != nil {
+		return nil, err
+	}
+	dict, err := ApplyContext(ctx, s.strategy, words, knowledge)
+	if err != nil {
+		return nil, err
 	}
-	with = s.strategy.Apply(with)
 	log.Debug().Strs("words", words).Strs("with", with).Msg(s.String())
-	return append(with[:1], s.strategy.Apply(words)...)
+	return append(with[:1], dict...), nil
+}
+
+// Workers sets the number of workers for the speculated strategy
+func (s *Speculate) Workers(n int) {
+	if s.strategy != nil {
+		SetWorkers(s.strategy, n)
+	}
+}
+
+// Tables sets the letter tables for the speculated strategy
+func (s *Speculate) Tables(tables *Tables) {
+	if s.strategy != nil {
+		SetTables(s.strategy, tables)
+	}
+}
+
+// Model sets the n-gram model for the speculated strategy
+func (s *Speculate) Model(model *NGramModel) {
+	if s.strategy != nil {
+		SetModel(s.strategy, model)
+	}
 }
 
 func NewSpeculator(words Dictionary, strategy Strategy) Strategy {
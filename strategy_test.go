@@ -1,10 +1,14 @@
 package qordle_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
 
 	"github.com/bzimmer/qordle"
 )
@@ -223,6 +227,187 @@ func TestPosition(t *testing.T) {
 	}
 }
 
+func TestInformed(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"grade", "brake", "frame", "irate", "erase", "crane", "sarin", "roads"}
+	for _, tt := range []struct {
+		name, pattern string
+		strategy      qordle.KnowledgeStrategy
+		words, result qordle.Dictionary
+	}{
+		{
+			name:     "frequency without knowledge",
+			strategy: new(qordle.InformedFrequency),
+			words:    words,
+			result:   new(qordle.Frequency).Apply(words),
+		},
+		{
+			name:     "frequency with knowledge",
+			pattern:  "cRAnE",
+			strategy: new(qordle.InformedFrequency),
+			words:    words,
+			result:   qordle.Dictionary{"sarin", "erase", "grade", "irate", "roads", "brake", "frame", "crane"},
+		},
+		{
+			name:     "frequency empty",
+			pattern:  "cRAnE",
+			strategy: new(qordle.InformedFrequency),
+			words:    qordle.Dictionary{},
+			result:   qordle.Dictionary{},
+		},
+		{
+			name:     "position without knowledge",
+			strategy: new(qordle.InformedPosition),
+			words:    words,
+			result:   new(qordle.Position).Apply(words),
+		},
+		{
+			name:     "position with knowledge",
+			pattern:  "cRAnE",
+			strategy: new(qordle.InformedPosition),
+			words:    words,
+			result:   qordle.Dictionary{"grade", "roads", "brake", "erase", "frame", "irate", "sarin", "crane"},
+		},
+		{
+			name:     "position empty",
+			pattern:  "cRAnE",
+			strategy: new(qordle.InformedPosition),
+			words:    qordle.Dictionary{},
+			result:   qordle.Dictionary{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			var knowledge *qordle.Knowledge
+			if tt.pattern != "" {
+				var err error
+				knowledge, err = qordle.NewKnowledge(tt.pattern)
+				a.NoError(err)
+			}
+			a.Equal(tt.result, qordle.ApplyKnowledge(tt.strategy, tt.words, knowledge))
+			a.Contains(tt.strategy.String(), "informed-")
+		})
+	}
+}
+
+func TestApplyKnowledge(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	knowledge, err := qordle.NewKnowledge("cRAnE")
+	a.NoError(err)
+	words := qordle.Dictionary{"grade", "brake", "frame", "irate", "erase", "crane", "sarin", "roads"}
+	informed := new(qordle.InformedFrequency)
+
+	// strategies without knowledge support fall back to Apply
+	a.Equal(new(qordle.Frequency).Apply(words), qordle.ApplyKnowledge(new(qordle.Frequency), words, knowledge))
+
+	// chains and speculation pass the knowledge to their strategies
+	expected := informed.ApplyKnowledge(words, knowledge)
+	a.Equal(expected, qordle.ApplyKnowledge(qordle.NewChain(informed), words, knowledge))
+	a.Equal(expected, qordle.ApplyKnowledge(qordle.NewSpeculator(words, informed), words, knowledge))
+	a.NotEqual(expected, qordle.NewChain(informed).Apply(words))
+}
+
+func TestApplyContext(t *testing.T) {
+	t.Parallel()
+	words := qordle.Dictionary{"easle", "false", "fause", "hatse", "haste"}
+	for _, tt := range []struct {
+		name, err string
+		strategy  qordle.Strategy
+		words     qordle.Dictionary
+		result    qordle.Dictionary
+		canceled  bool
+	}{
+		{
+			name:     "strategy without context support",
+			strategy: new(qordle.Alpha),
+			words:    words,
+			result:   qordle.Dictionary{"easle", "false", "fause", "haste", "hatse"},
+		},
+		{
+			name:     "elimination",
+			strategy: new(qordle.Elimination),
+			words:    words,
+			result:   qordle.Dictionary{"false", "hatse", "fause", "haste", "easle"},
+		},
+		{
+			name:     "canceled context",
+			strategy: new(qordle.Alpha),
+			words:    words,
+			canceled: true,
+			err:      context.Canceled.Error(),
+		},
+		{
+			name:     "canceled context with chain",
+			strategy: qordle.NewChain(new(qordle.Elimination), new(qordle.Frequency)),
+			words:    words,
+			canceled: true,
+			err:      context.Canceled.Error(),
+		},
+		{
+			name:     "elimination error",
+			strategy: new(qordle.Elimination),
+			words:    qordle.Dictionary{"abcde", "abcdef"},
+			err:      qordle.ErrInvalidLength.Error(),
+		},
+		{
+			name:     "elimination error in a chain",
+			strategy: qordle.NewChain(new(qordle.Elimination), new(qordle.Frequency)),
+			words:    qordle.Dictionary{"abcde", "abcdef"},
+			err:      qordle.ErrInvalidLength.Error(),
+		},
+		{
+			name:     "elimination error with speculation",
+			strategy: qordle.NewSpeculator(qordle.Dictionary{"abcde"}, new(qordle.Elimination)),
+			words:    qordle.Dictionary{"abcde", "abcdef", "bcdef", "cdefg", "defgh"},
+			err:      qordle.ErrInvalidLength.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.canceled {
+				cancel()
+			}
+			dictionary, err := qordle.ApplyContext(ctx, tt.strategy, tt.words, nil)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				a.Nil(dictionary)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, dictionary)
+		})
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	solutions, err := qordle.Read("solutions")
+	a.NoError(err)
+	guess, err := qordle.Guess("r.a.ise")
+	a.NoError(err)
+	words := qordle.Filter(solutions, guess)
+
+	expected := new(qordle.Elimination).Apply(words)
+	a.NotEmpty(expected)
+	for _, n := range []int{-1, 0, 1, 3, 1000} {
+		elimination := new(qordle.Elimination)
+		strategy := qordle.NewSpeculator(solutions, qordle.NewChain(elimination))
+		qordle.SetWorkers(strategy, n)
+		a.Equal(expected, elimination.Apply(words), "workers %d", n)
+		a.Equal(expected, strategy.Apply(words), "workers %d", n)
+	}
+	// no-op for strategies without a pool of workers
+	qordle.SetWorkers(new(qordle.Frequency), 2)
+}
+
 type identity struct{}
 
 func (s *identity) String() string {
@@ -385,10 +570,18 @@ func BenchmarkChain(b *testing.B) {
 }
 
 func TestStrategies(t *testing.T) {
+	a := assert.New(t)
 	tests := []harness{
 		{
 			name: "strategies",
 			args: []string{"strategies"},
+			after: func(c *cli.Context) error {
+				var res map[string]string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal(qordle.DefaultRegistry().Descriptions(), res)
+				return nil
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -1,6 +1,11 @@
 package qordle
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -9,12 +14,145 @@ const (
 	totalKey = "total"
 )
 
+// Suggested is a word ranked by the strategy
+type Suggested struct {
+	Word string `json:"word"`
+	// Rank is the position of the word in the order of the strategy, starting at one
+	Rank int `json:"rank"`
+	// Percentile is the fraction of the ranked words at or after the word in the order of the
+	// strategy, one for the best word, derived from the rank since strategies only order words
+	Percentile float64 `json:"percentile"`
+	// Answer is true if the word is a candidate consistent with the feedback
+	Answer bool `json:"answer"`
+	// Prior is the chance of the word being the answer before any strategy, uniform over the
+	// candidates and zero for the words which cannot be the answer
+	Prior float64 `json:"prior"`
+}
+
+// Suggestion is the ranked candidates and the state of the game from the feedback patterns
+type Suggestion struct {
+	Strategy string `json:"strategy"`
+	// Candidates is the number of words consistent with the feedback
+	Candidates int          `json:"candidates"`
+	Offset     int          `json:"offset"`
+	Words      []*Suggested `json:"words"`
+	Keyboard   *Keyboard    `json:"keyboard"`
+	// Letters is the fraction of the candidates containing each letter
+	Letters map[string]float64 `json:"letters"`
+}
+
+func (s *Suggestion) Header() []string {
+	return []string{"rank", "word", "percentile", "answer", "prior"}
+}
+
+func (s *Suggestion) Rows() [][]string {
+	rows := make([][]string, len(s.Words))
+	for i, w := range s.Words {
+		rows[i] = []string{
+			strconv.Itoa(w.Rank),
+			w.Word,
+			strconv.FormatFloat(w.Percentile, 'f', 4, 64),
+			strconv.FormatBool(w.Answer),
+			strconv.FormatFloat(w.Prior, 'f', 4, 64),
+		}
+	}
+	return rows
+}
+
+// Page restricts the suggested words to at most `limit` words, all if zero, after the first `offset`
+func (s *Suggestion) Page(offset, limit int) error {
+	words, err := Paginate(s.Words, offset, limit)
+	if err != nil {
+		return err
+	}
+	s.Words, s.Offset = words, offset
+	return nil
+}
+
+// Paginate returns at most `limit` values, all if zero, after the first `offset`
+func Paginate[T any](values []T, offset, limit int) ([]T, error) {
+	switch {
+	case offset < 0:
+		return nil, errors.New("offset must not be negative")
+	case limit < 0:
+		return nil, errors.New("limit must not be negative")
+	}
+	values = values[min(offset, len(values)):]
+	if limit > 0 {
+		values = values[:min(limit, len(values))]
+	}
+	return values, nil
+}
+
+// Suggest ranks the words of the dictionary consistent with the feedback patterns by the strategy
+// allowing for exactly `lies` false tiles in each pattern
+func Suggest(
+	ctx context.Context, strategy Strategy, dictionary Dictionary, lies int, patterns ...string,
+) (*Suggestion, error) {
+	guess, err := GuessLies(lies, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var knowledge *Knowledge
+	if lies == 0 {
+		// no single tile can be trusted when the feedback lies
+		knowledge, err = NewKnowledge(patterns...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	candidates := Filter(dictionary, IsLower(), guess)
+	ranked, err := ApplyContext(ctx, strategy, candidates, knowledge)
+	if err != nil {
+		return nil, err
+	}
+	answers := candidates.set()
+	suggestion := &Suggestion{
+		Strategy:   strategy.String(),
+		Candidates: len(candidates),
+		Words:      make([]*Suggested, len(ranked)),
+		Keyboard:   knowledge.Keyboard(),
+		Letters:    make(map[string]float64),
+	}
+	for i, word := range ranked {
+		w := &Suggested{
+			Word:       word,
+			Rank:       i + 1,
+			Percentile: float64(len(ranked)-i) / float64(len(ranked)),
+			Answer:     answers[word],
+		}
+		if w.Answer {
+			w.Prior = 1 / float64(len(candidates))
+		}
+		suggestion.Words[i] = w
+	}
+	for _, word := range candidates {
+		seen := make(map[rune]bool, len(word))
+		for _, r := range word {
+			if !seen[r] {
+				seen[r] = true
+				suggestion.Letters[string(r)]++
+			}
+		}
+	}
+	for letter := range suggestion.Letters {
+		suggestion.Letters[letter] /= float64(len(candidates))
+	}
+	return suggestion, nil
+}
+
 func CommandSuggest() *cli.Command {
 	return &cli.Command{
 		Name:      "suggest",
 		Category:  categoryWordle,
 		Usage:     "Suggest the next word to guess incorporating the already scored patterns",
 		ArgsUsage: "<pattern>...",
+		Description: "With --detailed the output includes the number of candidates, the score of " +
+			"each word and whether it could be the answer, the known state of the keyboard and the " +
+			"fraction of the candidates containing each letter. The query flags restrict the " +
+			"candidates further, eg --wildcard c?a?e --unique. With --lies k each pattern is " +
+			"assumed to have exactly k tiles with a false mark, as in Fibble, and a word remains a " +
+			"candidate if its marks for each guess differ from the pattern in exactly k tiles.",
 		Flags: append(
 			[]cli.Flag{
 				&cli.IntFlag{
@@ -22,20 +160,55 @@ func CommandSuggest() *cli.Command {
 					Usage: "word length",
 					Value: 5,
 				},
+				&cli.BoolFlag{
+					Name:    "detailed",
+					Aliases: []string{"d"},
+					Usage:   "include the percentiles, priors, candidates, keyboard and letter frequencies",
+				},
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "maximum number of words to suggest, all if zero",
+				},
+				&cli.IntFlag{
+					Name:  "offset",
+					Usage: "number of words to skip",
+				},
+				&cli.IntFlag{
+					Name:  "lies",
+					Usage: "number of tiles in each pattern with a false mark, as in Fibble",
+				},
 			},
-			append(wordlistFlags(), strategyFlags()...)...,
+			slices.Concat(queryFlags(), wordlistFlags(), exclusionFlags(), strategyFlags(), ngramFlags())...,
 		),
 		Action: func(c *cli.Context) error {
-			guess, err := Guess(c.Args().Slice()...)
+			dictionary, strategy, err := prepare(c, "possible", "solutions")
 			if err != nil {
 				return err
 			}
-			dictionary, strategy, err := prepare(c, "possible", "solutions")
+			dictionary, err = excluding(c, dictionary)
+			if err != nil {
+				return err
+			}
+			fns, err := queried(c).Filters()
 			if err != nil {
 				return err
 			}
-			dictionary = Filter(dictionary, IsLower(), Length(c.Int("length")), guess)
-			return Runtime(c).Encoder.Encode(strategy.Apply(dictionary))
+			dictionary = Filter(dictionary, append(fns, Length(c.Int("length")))...)
+			suggestion, err := Suggest(c.Context, strategy, dictionary, c.Int("lies"), c.Args().Slice()...)
+			if err != nil {
+				return err
+			}
+			if err = suggestion.Page(c.Int("offset"), c.Int("limit")); err != nil {
+				return err
+			}
+			if c.Bool("detailed") {
+				return Runtime(c).Encoder.Encode(suggestion)
+			}
+			words := make(Dictionary, len(suggestion.Words))
+			for i := range suggestion.Words {
+				words[i] = suggestion.Words[i].Word
+			}
+			return Runtime(c).Encoder.Encode(words)
 		},
 	}
 }
@@ -62,13 +235,112 @@ func CommandValidate() *cli.Command {
 	}
 }
 
+// standings returns the rank of each word, starting at one, under each strategy when ordering
+// the candidates together with the words
+// rankers are the built-in strategies ranking the words if none are specified, leaving out the
+// quadratic elimination strategy and any plugins
+var rankers = []string{
+	"alpha", "bigram", "frequency", "informed-frequency", "informed-position", "ngram", "position",
+}
+
+func standings(
+	c *cli.Context, names []string, candidates Dictionary, knowledge *Knowledge, tables *Tables, model *NGramModel,
+) (map[string]map[string]any, error) {
+	pool := candidates.union(Dictionary(c.Args().Slice()))
+	res := make(map[string]map[string]any, c.NArg())
+	for _, name := range names {
+		strategy, err := Runtime(c).Strategies.Strategy(name)
+		if err != nil {
+			return nil, err
+		}
+		SetTables(strategy, tables)
+		SetModel(strategy, model)
+		ranked, err := ApplyContext(c.Context, strategy, pool, knowledge)
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[string]int, len(ranked))
+		for i := range ranked {
+			index[ranked[i]] = i + 1
+		}
+		for _, word := range c.Args().Slice() {
+			if res[word] == nil {
+				res[word] = make(map[string]any, len(names))
+			}
+			res[word][strategy.String()] = map[string]int{"rank": index[word], "of": len(ranked)}
+		}
+	}
+	return res, nil
+}
+
 func CommandRanks() *cli.Command {
 	return &cli.Command{
 		Name:      ranksKey,
 		Category:  categoryWordle,
 		Usage:     "Detailed rank information from letter frequency tables",
 		ArgsUsage: "<word> ...",
+		Description: "The letter frequency tables are generated from the qordle word list unless " +
+			"patterns or a word list are specified in which case the tables are computed from the " +
+			"candidates remaining in the word list after filtering by the patterns. With candidates " +
+			"each word is also ranked among them by each strategy.",
+		Flags: append(
+			[]cli.Flag{
+				&cli.StringSliceFlag{
+					Name:    "pattern",
+					Aliases: []string{"p"},
+					Usage:   "filter the candidates by the scored `pattern`",
+				},
+				&cli.StringSliceFlag{
+					Name:    "strategy",
+					Aliases: []string{"s"},
+					Usage:   "rank the words by the strategy, the built-in strategies but elimination if not specified",
+				},
+				&cli.BoolFlag{
+					Name:  "no-tables",
+					Usage: "omit the letter frequency tables",
+				},
+			},
+			append(wordlistFlags(), ngramFlags()...)...,
+		),
 		Action: func(c *cli.Context) error {
+			patterns := c.StringSlice("pattern")
+			contextual := len(patterns) > 0 || c.IsSet("wordlist") || c.IsSet("Wordlist") || c.IsSet("intersect")
+			var wordlist []string
+			if len(patterns) > 0 {
+				wordlist = []string{"possible", "solutions"}
+			}
+			dictionary, err := wordlists(c, wordlist...)
+			if err != nil {
+				return err
+			}
+			guess, err := Guess(patterns...)
+			if err != nil {
+				return err
+			}
+			knowledge, err := NewKnowledge(patterns...)
+			if err != nil {
+				return err
+			}
+			candidates := Filter(dictionary, IsLower(), guess)
+
+			tables := DefaultTables()
+			var ranked map[string]map[string]any
+			if contextual {
+				tables = NewTables(candidates)
+				model, err := modeled(c, dictionary)
+				if err != nil {
+					return err
+				}
+				names := c.StringSlice("strategy")
+				if len(names) == 0 {
+					names = rankers
+				}
+				ranked, err = standings(c, names, candidates, knowledge, tables, model)
+				if err != nil {
+					return err
+				}
+			}
+
 			words := make(map[string]any, c.NArg())
 			for i := 0; i < c.NArg(); i++ {
 				var bt, ft, pt float64
@@ -78,30 +350,36 @@ func CommandRanks() *cli.Command {
 				f := make(map[int]float64, len(w))
 				b := make(map[string]float64, len(w))
 				for j := range w {
-					p[j] = positions[w[j]][j]
+					p[j] = tables.Positions[w[j]][j]
 					pt += p[j]
-					f[j] = frequencies[w[j]]
+					f[j] = tables.Frequencies[w[j]]
 					ft += f[j]
-
-					k := 0
-					for k+2 <= len(word) {
-						bt += bigrams[word[k:k+2]]
-						b[word[k:k+2]] = bigrams[word[k:k+2]]
-						k++
-					}
 				}
-				words[string(w)] = map[string]any{
+				for _, gram := range pairs(word) {
+					b[gram] = tables.Bigrams[gram]
+					bt += b[gram]
+				}
+				res := map[string]any{
 					"bigrams":     map[string]any{ranksKey: b, totalKey: bt},
 					"positions":   map[string]any{ranksKey: p, totalKey: pt},
 					"frequencies": map[string]any{ranksKey: f, totalKey: ft},
 				}
+				if contextual {
+					res["candidate"] = guess(word)
+					res["strategies"] = ranked[word]
+				}
+				words[string(w)] = res
 			}
-			return Runtime(c).Encoder.Encode(map[string]any{
-				"bigrams":     bigrams,
-				"frequencies": frequencies,
-				"positions":   positions,
-				"words":       words,
-			})
+			res := map[string]any{"words": words}
+			if contextual {
+				res["candidates"] = len(candidates)
+			}
+			if !c.Bool("no-tables") {
+				res["bigrams"] = tables.Bigrams
+				res["frequencies"] = tables.Frequencies
+				res["positions"] = tables.Positions
+			}
+			return Runtime(c).Encoder.Encode(res)
 		},
 	}
 }
@@ -112,14 +390,28 @@ func CommandOrder() *cli.Command {
 		Category:  categoryWordle,
 		Usage:     "Order the arguments per the strategy",
 		ArgsUsage: "word [, word, ...]",
-		Flags:     strategyFlags(),
+		Flags:     append(strategyFlags(), ngramFlags()...),
 		Action: func(c *cli.Context) error {
 			dictionary := Dictionary(c.Args().Slice())
 			_, strategy, err := prepare(c)
 			if err != nil {
 				return err
 			}
-			return Runtime(c).Encoder.Encode(strategy.Apply(dictionary))
+			// like the letter tables the model defaults to the embedded word lists
+			corpus, err := wordlists(c, "possible", "solutions")
+			if err != nil {
+				return err
+			}
+			model, err := modeled(c, corpus)
+			if err != nil {
+				return err
+			}
+			SetModel(strategy, model)
+			dictionary, err = ApplyContext(c.Context, strategy, dictionary, nil)
+			if err != nil {
+				return err
+			}
+			return Runtime(c).Encoder.Encode(dictionary)
 		},
 	}
 }
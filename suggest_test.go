@@ -27,6 +27,20 @@ func TestSuggestCommand(t *testing.T) {
 			name: "position",
 			args: []string{"suggest", "--strategy", "p", "raise", "fol.l.y"},
 		},
+		{
+			name: "informed frequency",
+			args: []string{"suggest", "--strategy", "informed-f", "cRAnE"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.NotEmpty(res)
+				for i := range res {
+					a.Equal("ra", res[i][1:3])
+				}
+				return nil
+			},
+		},
 		{
 			name: "combination",
 			args: []string{"suggest", "-s", "b", "-s", "f", "raise", "fol.l.y"},
@@ -69,6 +83,114 @@ func TestSuggestCommand(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "limit and offset",
+			args: []string{
+				"suggest", "-w", "solutions", "-S", "--strategy", "frequency", "--offset", "2", "--limit", "3",
+				"trai.n", ".o.u.nce", "bOUND",
+			},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"hound", "mound", "pound"}, res)
+				return nil
+			},
+		},
+		{
+			name: "detailed",
+			args: []string{
+				"suggest", "-w", "solutions", "-S", "--strategy", "frequency", "--detailed", "--limit", "2",
+				"trai.n", ".o.u.nce", "bOUND",
+			},
+			after: func(c *cli.Context) error {
+				var res qordle.Suggestion
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal("speculate{frequency}", res.Strategy)
+				a.Equal(6, res.Candidates)
+				a.Equal([]*qordle.Suggested{
+					{Word: "smash", Rank: 1, Percentile: 1, Answer: false},
+					{Word: "found", Rank: 2, Percentile: 6.0 / 7.0, Answer: true, Prior: 1.0 / 6.0},
+				}, res.Words)
+				a.Equal(map[int]string{1: "o", 2: "u", 3: "n", 4: "d"}, res.Keyboard.Green)
+				a.Equal([]string{"a", "b", "c", "e", "i", "r", "t"}, res.Keyboard.Absent)
+				a.Equal(1.0, res.Letters["o"])
+				a.InDelta(1.0/6.0, res.Letters["w"], 0.0001)
+				a.NotContains(res.Letters, "b")
+				return nil
+			},
+		},
+		{
+			name: "past the end",
+			args: []string{"suggest", "-w", "solutions", "--offset", "100", "bOUND"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Empty(res)
+				return nil
+			},
+		},
+		{
+			name: "query",
+			args: []string{"suggest", "-s", "alpha", "-w", "solutions", "--wildcard", "c?a?e", "--unique", "--lacks", "hv"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"crane", "crate", "craze"}, res)
+				return nil
+			},
+		},
+		{
+			name: "query with feedback",
+			args: []string{"suggest", "-s", "alpha", "-w", "solutions", "--vowels", "0", "--prefix", "g", "raise"},
+			after: func(c *cli.Context) error {
+				var res []string
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal([]string{"glyph"}, res)
+				return nil
+			},
+		},
+		{
+			name: "lies",
+			args: []string{"suggest", "-s", "alpha", "-w", "solutions", "--lies", "1", "--detailed", "PLEAt", "brain"},
+			after: func(c *cli.Context) error {
+				var res qordle.Suggestion
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				// both are consistent with exactly one false tile in each pattern
+				a.Equal(2, res.Candidates)
+				a.Equal("gleam", res.Words[0].Word)
+				a.Equal("pleat", res.Words[1].Word)
+				// no tile can be trusted so nothing is known of the keyboard
+				a.Empty(res.Keyboard.Green)
+				a.Empty(res.Keyboard.Absent)
+				return nil
+			},
+		},
+		{
+			name: "negative lies",
+			args: []string{"suggest", "--lies", "-1", "raise"},
+			err:  "lies must not be negative",
+		},
+		{
+			name: "invalid query",
+			args: []string{"suggest", "--regex", "(", "raise"},
+			err:  "invalid regex `(`",
+		},
+		{
+			name: "negative limit",
+			args: []string{"suggest", "--limit", "-1", "raise"},
+			err:  "limit must not be negative",
+		},
+		{
+			name: "negative offset",
+			args: []string{"suggest", "--offset", "-1", "raise"},
+			err:  "offset must not be negative",
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,6 +199,38 @@ func TestSuggestCommand(t *testing.T) {
 	}
 }
 
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name          string
+		offset, limit int
+		result        []int
+		err           string
+	}{
+		{name: "all", result: []int{1, 2, 3, 4}},
+		{name: "limit", limit: 2, result: []int{1, 2}},
+		{name: "offset", offset: 1, result: []int{2, 3, 4}},
+		{name: "offset and limit", offset: 1, limit: 2, result: []int{2, 3}},
+		{name: "limit past the end", offset: 3, limit: 2, result: []int{4}},
+		{name: "offset past the end", offset: 5, result: []int{}},
+		{name: "negative offset", offset: -1, err: "offset must not be negative"},
+		{name: "negative limit", limit: -1, err: "limit must not be negative"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			res, err := qordle.Paginate([]int{1, 2, 3, 4}, tt.offset, tt.limit)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, res)
+		})
+	}
+}
+
 func TestValidateCommand(t *testing.T) {
 	for _, tt := range []harness{
 		{
@@ -119,6 +273,7 @@ func TestValidateCommand(t *testing.T) {
 }
 
 func TestRanksCommand(t *testing.T) {
+	a := assert.New(t)
 	for _, tt := range []harness{
 		{
 			name: "simple",
@@ -128,6 +283,88 @@ func TestRanksCommand(t *testing.T) {
 			name: "words",
 			args: []string{"ranks", "brown"},
 		},
+		{
+			name: "wordlist",
+			args: []string{"ranks", "-w", "solutions", "-s", "alpha", "brown"},
+			after: func(c *cli.Context) error {
+				var res struct {
+					Positions map[rune]map[int]float64 `json:"positions"`
+				}
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				// the tables are computed from the five letter solutions
+				a.Len(res.Positions['b'], 5)
+				return nil
+			},
+		},
+		{
+			name: "patterns",
+			args: []string{"ranks", "--no-tables", "-p", "r.a.ise", "-s", "freq", "-s", "alpha", "chain", "brain"},
+			after: func(c *cli.Context) error {
+				var res struct {
+					Candidates int `json:"candidates"`
+					Words      map[string]struct {
+						Candidate  bool                      `json:"candidate"`
+						Strategies map[string]map[string]int `json:"strategies"`
+					} `json:"words"`
+					Positions map[rune]map[int]float64 `json:"positions"`
+				}
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Equal(203, res.Candidates)
+				a.Nil(res.Positions)
+				a.True(res.Words["chain"].Candidate)
+				a.False(res.Words["brain"].Candidate)
+				a.Equal(map[string]int{"rank": 60, "of": 204}, res.Words["chain"].Strategies["alpha"])
+				a.Equal(map[string]int{"rank": 57, "of": 204}, res.Words["brain"].Strategies["alpha"])
+				a.Contains(res.Words["chain"].Strategies, "frequency")
+				return nil
+			},
+		},
+		{
+			name: "default strategies",
+			args: []string{"ranks", "--no-tables", "-w", "solutions", "brown"},
+			after: func(c *cli.Context) error {
+				var res struct {
+					Words map[string]struct {
+						Strategies map[string]map[string]int `json:"strategies"`
+					} `json:"words"`
+				}
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Len(res.Words["brown"].Strategies, 7)
+				a.Contains(res.Words["brown"].Strategies, "ngram")
+				a.NotContains(res.Words["brown"].Strategies, "elimination")
+				return nil
+			},
+		},
+		{
+			name: "no tables",
+			args: []string{"ranks", "--no-tables", "brown"},
+			after: func(c *cli.Context) error {
+				var res map[string]any
+				dec := json.NewDecoder(c.App.Writer.(io.Reader))
+				a.NoError(dec.Decode(&res))
+				a.Len(res, 1)
+				a.Contains(res, "words")
+				return nil
+			},
+		},
+		{
+			name: "invalid pattern",
+			args: []string{"ranks", "-p", "r.a.i.s.e.", "brown"},
+			err:  "invalid pattern format",
+		},
+		{
+			name: "unknown strategy",
+			args: []string{"ranks", "-p", "r.a.ise", "-s", "foobar", "brown"},
+			err:  "unknown strategy `foobar`",
+		},
+		{
+			name: "invalid wordlist",
+			args: []string{"ranks", "-w", "nonexistent", "brown"},
+			err:  "invalid wordlist `nonexistent`",
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,84 @@
+package qordle
+
+import (
+	"bytes"
+	"io"
+	"unicode"
+)
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiExact     = "\x1b[1;97;42m"
+	ansiMisplaced = "\x1b[1;97;43m"
+	ansiMiss      = "\x1b[1;97;100m"
+)
+
+// Renderer is implemented by values with a terminal representation as tiles
+type Renderer interface {
+	// Render writes the value as tiles
+	Render(w io.Writer, tiles *Tiles) error
+}
+
+// Tiles renders guesses as Wordle tiles
+type Tiles struct {
+	color bool
+}
+
+// NewTiles creates tiles with ANSI colored backgrounds or, if not colored,
+// with brackets for exact and parentheses for misplaced letters
+func NewTiles(color bool) *Tiles {
+	return &Tiles{color: color}
+}
+
+func (t *Tiles) tile(buf *bytes.Buffer, letter rune, mark Mark) {
+	letter = unicode.ToUpper(letter)
+	if t.color {
+		switch mark {
+		case MarkExact:
+			buf.WriteString(ansiExact)
+		case MarkMisplaced:
+			buf.WriteString(ansiMisplaced)
+		case MarkMiss:
+			buf.WriteString(ansiMiss)
+		}
+		buf.WriteRune(' ')
+		buf.WriteRune(letter)
+		buf.WriteRune(' ')
+		buf.WriteString(ansiReset)
+		return
+	}
+	switch mark {
+	case MarkExact:
+		buf.WriteRune('[')
+		buf.WriteRune(letter)
+		buf.WriteRune(']')
+	case MarkMisplaced:
+		buf.WriteRune('(')
+		buf.WriteRune(letter)
+		buf.WriteRune(')')
+	case MarkMiss:
+		buf.WriteRune(' ')
+		buf.WriteRune(letter)
+		buf.WriteRune(' ')
+	}
+}
+
+// Render writes a line of tiles for each guess marked against the secret
+func (t *Tiles) Render(w io.Writer, secret string, guesses ...string) error {
+	checks, err := Check(secret, guesses...)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for i := range checks {
+		for j, letter := range []rune(guesses[i]) {
+			if j > 0 {
+				buf.WriteRune(' ')
+			}
+			t.tile(&buf, letter, checks[i][j])
+		}
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,115 @@
+package qordle_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bzimmer/qordle"
+)
+
+func TestTiles(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name, secret, result, err string
+		guesses                   []string
+		color                     bool
+	}{
+		{
+			name:    "monochrome",
+			secret:  "buyer",
+			guesses: []string{"brain", "beret"},
+			result:  "[B] (R)  A   I   N \n[B]  E  (R) [E]  T \n",
+		},
+		{
+			name:    "color",
+			secret:  "buyer",
+			guesses: []string{"beret"},
+			color:   true,
+			result: "\x1b[1;97;42m B \x1b[0m \x1b[1;97;100m E \x1b[0m \x1b[1;97;43m R \x1b[0m " +
+				"\x1b[1;97;42m E \x1b[0m \x1b[1;97;100m T \x1b[0m\n",
+		},
+		{
+			name:    "different lengths",
+			secret:  "buyer",
+			guesses: []string{"tables"},
+			err:     qordle.ErrInvalidLength.Error(),
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			var buf bytes.Buffer
+			err := qordle.NewTiles(tt.color).Render(&buf, tt.secret, tt.guesses...)
+			if tt.err != "" {
+				a.EqualError(err, tt.err)
+				return
+			}
+			a.NoError(err)
+			a.Equal(tt.result, buf.String())
+		})
+	}
+}
+
+func TestTilesCommands(t *testing.T) {
+	a := assert.New(t)
+	tiles := func(format string) cli.BeforeFunc {
+		return func(c *cli.Context) error {
+			enc, err := qordle.NewEncoder(c.App.Writer, format, qordle.WithTiles(qordle.NewTiles(false)))
+			qordle.Runtime(c).Encoder = enc
+			return err
+		}
+	}
+	output := func(c *cli.Context) string {
+		return c.App.Writer.(*bytes.Buffer).String()
+	}
+	for _, tt := range []struct {
+		harness
+		cmd func() *cli.Command
+	}{
+		{
+			cmd: qordle.CommandScore,
+			harness: harness{
+				name:   "score tiles",
+				args:   []string{"score", "buyer", "brain", "beret"},
+				before: tiles("text"),
+				after: func(c *cli.Context) error {
+					a.Equal("[B] (R)  A   I   N \n[B]  E  (R) [E]  T \n", output(c))
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandScore,
+			harness: harness{
+				name:   "score json ignores tiles",
+				args:   []string{"score", "buyer", "brain", "beret"},
+				before: tiles("json"),
+				after: func(c *cli.Context) error {
+					a.Equal("[\"B.rain\",\"Be.rEt\"]\n", output(c))
+					return nil
+				},
+			},
+		},
+		{
+			cmd: qordle.CommandPlay,
+			harness: harness{
+				name:   "play tiles",
+				args:   []string{"play", "--start", "brain", "-s", "frequency", "raise"},
+				before: tiles("text"),
+				after: func(c *cli.Context) error {
+					a.Equal(" B  (R) (A) (I)  N \n[R] [A] [I] [S] [E]\n\n", output(c))
+					return nil
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, &tt.harness, tt.cmd)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package qordle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Stats describes the contents of a word list as read, before any normalization
+type Stats struct {
+	Name    string      `json:"name"`
+	Words   int         `json:"words"`
+	Unique  int         `json:"unique"`
+	Lengths map[int]int `json:"lengths"`
+	// Uppercase are the words not starting with a lowercase letter, dropped by IsLower
+	Uppercase []string `json:"uppercase"`
+	// NonASCII are the words containing characters outside of ASCII
+	NonASCII []string `json:"non_ascii"`
+	// Duplicates are the words appearing more than once
+	Duplicates []string `json:"duplicates"`
+	// Overlap is the number of unique words shared with each of the other lists
+	Overlap map[string]int `json:"overlap"`
+}
+
+func (s *Stats) Header() []string {
+	return []string{"name", "words", "unique", "lengths", "uppercase", "non_ascii", "duplicates", "overlap"}
+}
+
+func (s *Stats) Rows() [][]string {
+	lengths := make([]int, 0, len(s.Lengths))
+	for n := range s.Lengths {
+		lengths = append(lengths, n)
+	}
+	sort.Ints(lengths)
+	bins := make([]string, len(lengths))
+	for i, n := range lengths {
+		bins[i] = fmt.Sprintf("%d:%d", n, s.Lengths[n])
+	}
+	names := make([]string, 0, len(s.Overlap))
+	for name := range s.Overlap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	overlap := make([]string, len(names))
+	for i, name := range names {
+		overlap[i] = fmt.Sprintf("%s:%d", name, s.Overlap[name])
+	}
+	return [][]string{{
+		s.Name,
+		strconv.Itoa(s.Words),
+		strconv.Itoa(s.Unique),
+		strings.Join(bins, " "),
+		strconv.Itoa(len(s.Uppercase)),
+		strconv.Itoa(len(s.NonASCII)),
+		strconv.Itoa(len(s.Duplicates)),
+		strings.Join(overlap, " "),
+	}}
+}
+
+// Diff describes the changes from one word list to another
+type Diff struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Common  int      `json:"common"`
+}
+
+func (d *Diff) Header() []string {
+	return []string{"change", "word"}
+}
+
+func (d *Diff) Rows() [][]string {
+	rows := make([][]string, 0, len(d.Added)+len(d.Removed))
+	for _, word := range d.Removed {
+		rows = append(rows, []string{"-", word})
+	}
+	for _, word := range d.Added {
+		rows = append(rows, []string{"+", word})
+	}
+	return rows
+}
+
+// NewStats computes the statistics of the word list
+func NewStats(name string, words Dictionary) *Stats {
+	s := &Stats{
+		Name:       name,
+		Words:      len(words),
+		Lengths:    make(map[int]int),
+		Uppercase:  []string{},
+		NonASCII:   []string{},
+		Duplicates: []string{},
+		Overlap:    make(map[string]int),
+	}
+	counts := make(map[string]int, len(words))
+	for _, word := range words {
+		counts[word]++
+		if counts[word] > 1 {
+			if counts[word] == 2 {
+				s.Duplicates = append(s.Duplicates, word)
+			}
+			continue
+		}
+		s.Lengths[len([]rune(word))]++
+		if word == "" || !unicode.IsLower([]rune(word)[0]) {
+			s.Uppercase = append(s.Uppercase, word)
+		}
+		if strings.IndexFunc(word, func(r rune) bool { return r > unicode.MaxASCII }) >= 0 {
+			s.NonASCII = append(s.NonASCII, word)
+		}
+	}
+	s.Unique = len(counts)
+	return s
+}
+
+// unique returns the first occurrence of each word, in order
+func unique(words Dictionary) Dictionary {
+	seen := make(map[string]bool, len(words))
+	res := make(Dictionary, 0, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			res = append(res, word)
+		}
+	}
+	return res
+}
+
+// NewDiff computes the words added and removed from one word list to another
+func NewDiff(from, to string, a, b Dictionary) *Diff {
+	a, b = unique(a), unique(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	removed, added := a.Difference(b), b.Difference(a)
+	if removed == nil {
+		removed = Dictionary{}
+	}
+	if added == nil {
+		added = Dictionary{}
+	}
+	return &Diff{
+		From:    from,
+		To:      to,
+		Added:   added,
+		Removed: removed,
+		Common:  len(a) - len(removed),
+	}
+}
+
+// load the word list exactly as read from stdin, an embedded word list or an external file
+func load(c *cli.Context, wordlist string) (Dictionary, error) {
+	return lookup(c, wordlist, readRaw)
+}
+
+func commandWordlistsShow() *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "Show the words of the word lists",
+		ArgsUsage: "<wordlist> [, <wordlist>]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return errors.New("missing wordlist")
+			}
+			for _, name := range c.Args().Slice() {
+				words, err := load(c, name)
+				if err != nil {
+					return err
+				}
+				if err = Runtime(c).Encoder.Encode(words); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func commandWordlistsStats() *cli.Command {
+	return &cli.Command{
+		Name:      "stats",
+		Usage:     "Audit the word lists, all embedded word lists if none are specified",
+		ArgsUsage: "[<wordlist>, ...]",
+		Action: func(c *cli.Context) error {
+			names := c.Args().Slice()
+			if len(names) == 0 {
+				var err error
+				names, err = embedded()
+				if err != nil {
+					return err
+				}
+			}
+			lists := make([]Dictionary, len(names))
+			stats := make([]*Stats, len(names))
+			for i, name := range names {
+				words, err := load(c, name)
+				if err != nil {
+					return err
+				}
+				lists[i], stats[i] = words, NewStats(name, words)
+			}
+			for i := range names {
+				for j := range names {
+					if i != j {
+						stats[i].Overlap[names[j]] = len(unique(lists[i]).Intersect(lists[j]))
+					}
+				}
+			}
+			return Runtime(c).Encoder.Encode(stats)
+		},
+	}
+}
+
+func commandWordlistsDiff() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Show the words added and removed from one word list, or version, to another",
+		ArgsUsage: "<from> <to>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				return errors.New("expected two wordlists")
+			}
+			from, to := c.Args().Get(0), c.Args().Get(1)
+			lists := make([]Dictionary, 2)
+			for i, name := range []string{from, to} {
+				words, err := load(c, name)
+				if err != nil {
+					return err
+				}
+				lists[i] = words
+			}
+			return Runtime(c).Encoder.Encode(NewDiff(from, to, lists[0], lists[1]))
+		},
+	}
+}